@@ -15,6 +15,7 @@
 package ogmigo
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -23,9 +24,11 @@ import (
 	"net"
 	"os"
 	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/buger/jsonparser"
 	"github.com/gorilla/websocket"
 	"golang.org/x/sync/errgroup"
 
@@ -61,12 +64,35 @@ func (c *ChainSync) Close() error {
 // ChainSyncFunc callback containing json encoded chainsync.Response
 type ChainSyncFunc func(ctx context.Context, data []byte) error
 
+// ChainSyncResponseFunc callback containing both the json encoded and the decoded chainsync.Response
+type ChainSyncResponseFunc func(ctx context.Context, data []byte, response chainsync.Response) error
+
+// TypedChainSyncFunc adapts fn to a ChainSyncFunc that decodes each message once, allowing
+// callers to both persist the original bytes and act on the typed response
+func TypedChainSyncFunc(fn ChainSyncResponseFunc) ChainSyncFunc {
+	return func(ctx context.Context, data []byte) error {
+		var response chainsync.Response
+		if err := json.Unmarshal(data, &response); err != nil {
+			return fmt.Errorf("failed to decode chainsync response: %w", err)
+		}
+		return fn(ctx, data, response)
+	}
+}
+
 // ChainSyncOptions configuration parameters
 type ChainSyncOptions struct {
-	minSlot   uint64           // minSlot to begin invoking ChainSyncFunc; 0 for always invoke func
-	points    chainsync.Points // points to attempt initial intersection
-	reconnect bool             // reconnect to ogmios if connection drops
-	store     Store            // store of points
+	confirmationDepth  int               // confirmationDepth below the tip before blocks are delivered
+	maxIntersectPoints int               // maxIntersectPoints sent when finding the intersection
+	minSlot            uint64            // minSlot to begin invoking ChainSyncFunc; 0 for always invoke func
+	onCaughtUp         func()            // onCaughtUp invoked the first time ChainSync reaches the tip
+	onEraTransition    EraTransitionFunc // onEraTransition invoked when consecutive blocks differ in era
+	points             chainsync.Points  // points to attempt initial intersection
+	progress           ProgressFunc      // progress receives processed and tip points periodically
+	progressInterval   time.Duration     // progressInterval between calls to progress
+	reconnect          bool              // reconnect to ogmios if connection drops
+	rollbackOnly       RollbackFunc      // rollbackOnly replaces the ChainSyncFunc, receiving only rollbacks
+	rollbackDebounce   time.Duration     // rollbackDebounce window in which consecutive rollbacks are coalesced
+	store              Store             // store of points
 }
 
 func buildChainSyncOptions(opts ...ChainSyncOption) ChainSyncOptions {
@@ -77,6 +103,12 @@ func buildChainSyncOptions(opts ...ChainSyncOption) ChainSyncOptions {
 	if options.store == nil {
 		options.store = nopStore{}
 	}
+	if options.progressInterval <= 0 {
+		options.progressInterval = 5 * time.Second
+	}
+	if options.maxIntersectPoints <= 0 {
+		options.maxIntersectPoints = 5
+	}
 	return options
 }
 
@@ -90,6 +122,24 @@ func WithMinSlot(slot uint64) ChainSyncOption {
 	}
 }
 
+// WithConfirmationDepth delays delivery of each block until it is at least n blocks below the
+// tip, buffering the most recent n blocks.  Rollbacks of buffered blocks are absorbed; only
+// rollbacks beyond the last delivered block reach the callback.  Points saved to the Store
+// are those of delivered blocks so buffered blocks are replayed on restart.
+func WithConfirmationDepth(n int) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.confirmationDepth = n
+	}
+}
+
+// WithMaxIntersectPoints limits the number of points, newest first, sent when finding the
+// intersection; defaults to 5
+func WithMaxIntersectPoints(n int) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.maxIntersectPoints = n
+	}
+}
+
 // WithPoints allows starting from an optional point
 func WithPoints(points ...chainsync.Point) ChainSyncOption {
 	return func(opts *ChainSyncOptions) {
@@ -97,6 +147,52 @@ func WithPoints(points ...chainsync.Point) ChainSyncOption {
 	}
 }
 
+// WithOnCaughtUp invokes fn the first time ChainSync processes the block at the tip
+func WithOnCaughtUp(fn func()) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.onCaughtUp = fn
+	}
+}
+
+// EraTransitionFunc receives the eras of consecutive blocks that differ along with the
+// point of the first block of the new era
+type EraTransitionFunc func(from, to chainsync.Era, at chainsync.PointStruct)
+
+// WithOnEraTransition invokes fn, prior to the callback, whenever a block's era differs from
+// that of the previous block e.g. at a hard fork.  Following a rollback across a hard fork,
+// fn is invoked again with the eras reversed.
+func WithOnEraTransition(fn EraTransitionFunc) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.onEraTransition = fn
+	}
+}
+
+// ProgressFunc receives the most recently processed point along with the tip
+// reported by ogmios
+type ProgressFunc func(processed, tip chainsync.PointStruct)
+
+// WithProgress periodically reports the last processed point and the current tip,
+// allowing callers to monitor how far behind the tip ChainSync is; see WithProgressInterval
+func WithProgress(fn ProgressFunc) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.progress = fn
+	}
+}
+
+// WithProgressInterval sets the minimum time between calls to the WithProgress
+// callback; defaults to 5s
+func WithProgressInterval(d time.Duration) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.progressInterval = d
+	}
+}
+
+// WithStartFromOrigin replays the blockchain from the origin; as with WithPoints, points
+// loaded from the Store take precedence
+func WithStartFromOrigin() ChainSyncOption {
+	return WithPoints(chainsync.OriginPoints()...)
+}
+
 // WithReconnect attempt to reconnect to ogmios if connection drops
 func WithReconnect(enabled bool) ChainSyncOption {
 	return func(opts *ChainSyncOptions) {
@@ -104,6 +200,27 @@ func WithReconnect(enabled bool) ChainSyncOption {
 	}
 }
 
+// WithRollbackDebounce coalesces consecutive rollbacks received within the window, d,
+// and delivers only the deepest one to the callback.  A held rollback is delivered
+// before the next message that is not a rollback, or once held for d.
+func WithRollbackDebounce(d time.Duration) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.rollbackDebounce = d
+	}
+}
+
+// RollbackFunc receives the point rolled back to along with the tip reported by ogmios
+type RollbackFunc func(ctx context.Context, point chainsync.Point, tip chainsync.PointStruct) error
+
+// WithRollbackOnly invokes fn solely for rollbacks in place of the ChainSyncFunc passed to
+// ChainSync, which may be nil.  RollForward messages are discarded without being decoded
+// though, as with any callback, the points processed are still saved to the Store.
+func WithRollbackOnly(fn RollbackFunc) ChainSyncOption {
+	return func(opts *ChainSyncOptions) {
+		opts.rollbackOnly = fn
+	}
+}
+
 // WithStore specifies store to persist points to; defaults to no persistence
 func WithStore(store Store) ChainSyncOption {
 	return func(opts *ChainSyncOptions) {
@@ -116,20 +233,29 @@ func WithStore(store Store) ChainSyncOption {
 // be overridden via WithPoints and WithStore
 func (c *Client) ChainSync(ctx context.Context, callback ChainSyncFunc, opts ...ChainSyncOption) (*ChainSync, error) {
 	options := buildChainSyncOptions(opts...)
+	if fn := options.onCaughtUp; fn != nil {
+		var once sync.Once
+		options.onCaughtUp = func() { once.Do(fn) } // only notify once across reconnects
+	}
+	if fn := options.rollbackOnly; fn != nil {
+		callback = rollbackOnly(fn)
+	}
+	if network := c.options.network; network != "" {
+		callback = networkCheck(callback, network)
+	}
+	if fn := options.onEraTransition; fn != nil {
+		callback = eraTransition(callback, fn) // tracks the era across reconnects
+	}
+	if n := options.confirmationDepth; n > 0 {
+		confirmed := &confirmed{callback: callback, depth: uint64(n)}
+		callback = confirmed.chainSyncFunc
+		options.store = confirmedStore{Store: options.store, confirmed: confirmed}
+	}
 
-	done := make(chan struct{})
-	errs := make(chan error, 1)
-	ctx, cancel := context.WithCancel(ctx)
-
-	go func() {
-		defer close(done)
-
-		var (
-			timeout = 10 * time.Second
-			err     error
-		)
+	closer := StartChainSync(ctx, func(ctx context.Context) error {
+		timeout := 10 * time.Second
 		for {
-			err = c.doChainSync(ctx, callback, options)
+			err := c.doChainSync(ctx, callback, options)
 			if err != nil && isTemporaryError(err) {
 				if options.reconnect {
 					c.options.logger.Info("websocket connection error: will retry",
@@ -139,36 +265,246 @@ func (c *Client) ChainSync(ctx context.Context, callback ChainSyncFunc, opts ...
 
 					select {
 					case <-ctx.Done():
-						return
+						return nil
 					case <-time.After(timeout):
 						continue
 					}
 				}
 			}
 
-			break
+			return err
 		}
-		errs <- err
+	})
+	closer.logger = c.logger
+
+	return closer, nil
+}
+
+// StartChainSync runs fn in the background returning a ChainSync whose Close cancels the
+// context passed to fn and returns the error from fn.  StartChainSync allows alternate
+// ChainSyncer implementations, such as fakes, to return a ChainSync.
+func StartChainSync(ctx context.Context, fn func(ctx context.Context) error) *ChainSync {
+	done := make(chan struct{})
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		defer close(done)
+		errs <- fn(ctx)
 	}()
 
 	return &ChainSync{
 		cancel: cancel,
 		errs:   errs,
 		done:   done,
-		logger: c.logger,
-	}, nil
+	}
+}
+
+// DumpChain replays the blockchain writing the result of each RollForward and RollBackward
+// to w as newline delimited json; checkpoints are saved to the Store as with ChainSync.
+// DumpChain blocks until the context is canceled or the sync fails.
+func (c *Client) DumpChain(ctx context.Context, w io.Writer, opts ...ChainSyncOption) error {
+	buf := bytes.NewBuffer(nil)
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		result, dataType, _, err := jsonparser.Get(data, "result")
+		if err != nil || dataType != jsonparser.Object {
+			return nil
+		}
+		_, _, _, forwardErr := jsonparser.Get(result, "RollForward")
+		_, _, _, backwardErr := jsonparser.Get(result, "RollBackward")
+		if forwardErr != nil && backwardErr != nil {
+			return nil // intersections are not part of the chain
+		}
+
+		buf.Reset()
+		if err := json.Compact(buf, result); err != nil {
+			return fmt.Errorf("failed to compact result: %w", err)
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write result: %w", err)
+		}
+		return nil
+	}
+
+	closer, err := c.ChainSync(ctx, callback, opts...)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-closer.Done():
+	}
+	return closer.Close()
+}
+
+// SyncRange replays the blockchain from the intersection with from up to and including the
+// block at the slot of to, returning once that block has been processed by the callback.
+// Blocks beyond the slot of to are not delivered.  As with ChainSync, points loaded from a
+// Store take precedence over from, allowing an interrupted range to be resumed.
+func (c *Client) SyncRange(ctx context.Context, from, to chainsync.Point, callback ChainSyncFunc, opts ...ChainSyncOption) error {
+	end, ok := to.PointStruct()
+	if !ok {
+		return fmt.Errorf("failed to sync range: to must be a block, not %v", to)
+	}
+
+	var (
+		reached = make(chan struct{})
+		done    bool
+	)
+	var wrapped ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		if done {
+			return nil // discard pipelined messages
+		}
+
+		point, ok := getPoint(data)
+		if !ok {
+			return callback(ctx, data)
+		}
+		ps, _ := point.PointStruct()
+		if ps.Slot <= end.Slot {
+			if err := callback(ctx, data); err != nil {
+				return err
+			}
+		}
+		if ps.Slot >= end.Slot {
+			done = true
+			close(reached)
+		}
+		return nil
+	}
+
+	opts = append([]ChainSyncOption{WithPoints(from)}, opts...)
+	closer, err := c.ChainSync(ctx, wrapped, opts...)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-reached:
+		return closer.Close()
+	case <-ctx.Done():
+		closer.Close()
+		return ctx.Err()
+	case <-closer.Done():
+		if err := closer.Close(); err != nil {
+			return err
+		}
+		select {
+		case <-reached:
+			return nil
+		default:
+			return fmt.Errorf("failed to sync range: chainsync stopped before reaching %v", to)
+		}
+	}
+}
+
+// CollectBlocks syncs from the points provided via WithPoints, or the store, and returns the
+// next n blocks rolled forward.  Collected blocks that are rolled back are discarded and
+// replaced by those that follow.  Byron blocks, which cannot be represented as a
+// chainsync.Block, are skipped.
+func (c *Client) CollectBlocks(ctx context.Context, n int, opts ...ChainSyncOption) ([]chainsync.Block, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	var (
+		blocks  []chainsync.Block
+		reached = make(chan struct{})
+		done    bool
+	)
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		if done {
+			return nil // discard pipelined messages
+		}
+
+		if point, ok := getRollBackward(data); ok {
+			ps, isBlock := point.PointStruct()
+			for len(blocks) > 0 && (!isBlock || blocks[len(blocks)-1].Header.Slot > ps.Slot) {
+				blocks = blocks[:len(blocks)-1]
+			}
+			return nil
+		}
+
+		var response chainsync.Response
+		if err := json.Unmarshal(data, &response); err != nil {
+			return fmt.Errorf("failed to decode chainsync response: %w", err)
+		}
+		if response.Result == nil || response.Result.RollForward == nil {
+			return nil
+		}
+		block := shelleyBlock(response.Result.RollForward.Block)
+		if block == nil {
+			return nil
+		}
+
+		blocks = append(blocks, *block)
+		if len(blocks) == n {
+			done = true
+			close(reached)
+		}
+		return nil
+	}
+
+	closer, err := c.ChainSync(ctx, callback, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-reached:
+		if err := closer.Close(); err != nil {
+			return nil, err
+		}
+		return blocks, nil
+	case <-ctx.Done():
+		closer.Close()
+		return nil, ctx.Err()
+	case <-closer.Done():
+		if err := closer.Close(); err != nil {
+			return nil, err
+		}
+		select {
+		case <-reached:
+			return blocks, nil
+		default:
+			return nil, fmt.Errorf("failed to collect blocks: chainsync stopped after %v of %v blocks", len(blocks), n)
+		}
+	}
+}
+
+// shelleyBlock returns the block rolled forward, or nil for byron blocks
+func shelleyBlock(block chainsync.RollForwardBlock) *chainsync.Block {
+	switch {
+	case block.Shelley != nil:
+		return block.Shelley
+	case block.Allegra != nil:
+		return block.Allegra
+	case block.Mary != nil:
+		return block.Mary
+	case block.Alonzo != nil:
+		return block.Alonzo
+	default:
+		return block.Babbage
+	}
 }
 
 func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, options ChainSyncOptions) error {
-	conn, _, err := websocket.DefaultDialer.Dial(c.options.endpoint, nil)
+	conn, _, err := c.options.websocketDialer().Dial(c.options.endpoint, nil)
 	if err != nil {
-		return fmt.Errorf("failed to connect to ogmios, %v: %w", c.options.endpoint, err)
+		return &UnreachableError{Endpoint: c.options.endpoint, Err: err}
 	}
 
-	init, err := getInit(ctx, options.store, options.points...)
+	payload, err := getInit(ctx, options.store, options.maxIntersectPoints, options.points...)
 	if err != nil {
 		return fmt.Errorf("failed to create init message: %w", err)
 	}
+	c.mirror(payload)
+	init, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode init message: %w", err)
+	}
 
 	group, ctx := errgroup.WithContext(ctx)
 	group.Go(func() error {
@@ -199,6 +535,7 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 	}
 
 	group.Go(func() error {
+		c.options.tap(FrameOutbound, init)
 		if err := conn.WriteMessage(websocket.TextMessage, init); err != nil {
 			var oe *net.OpError
 			if ok := errors.As(err, &oe); ok {
@@ -209,12 +546,18 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 			return fmt.Errorf("failed to write FindIntersect: %w", err)
 		}
 
-		next := []byte(`{"type":"jsonwsp/request","version":"1.0","servicename":"ogmios","methodname":"RequestNext","args":{}}`)
 		for {
 			select {
 			case <-ctx.Done():
 				return nil
 			case <-ch:
+				payload := makePayload("RequestNext", Map{})
+				c.mirror(payload)
+				next, err := json.Marshal(payload)
+				if err != nil {
+					return fmt.Errorf("failed to encode RequestNext: %w", err)
+				}
+				c.options.tap(FrameOutbound, next)
 				if err := conn.WriteMessage(websocket.TextMessage, next); err != nil {
 					return fmt.Errorf("failed to write RequestNext: %w", err)
 				}
@@ -222,10 +565,14 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 		}
 	})
 
+	type frame struct {
+		messageType int
+		data        []byte
+	}
+	frames := make(chan frame)
 	group.Go(func() error {
-		checkSlot := options.minSlot > 0
-		last := newCircular(3)
-		for n := uint64(1); ; n++ {
+		defer close(frames)
+		for {
 			messageType, data, err := conn.ReadMessage()
 			if err != nil {
 				if errors.Is(err, io.EOF) {
@@ -239,15 +586,73 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 				}
 				return fmt.Errorf("failed to read message from ogmios: %w", err)
 			}
+			c.options.tap(FrameInbound, data)
 
 			select {
 			case <-ctx.Done():
-				if point, ok := getPoint(last.list()...); ok {
-					if err := options.store.Save(context.Background(), point); err != nil {
-						return fmt.Errorf("chainsync client failed: %w", err)
-					}
-				}
 				return nil
+			case frames <- frame{messageType: messageType, data: data}:
+			}
+		}
+	})
+
+	group.Go(func() error {
+		checkSlot := options.minSlot > 0
+		last := newCircular(3)
+		lastProgress := time.Now()
+		caughtUp := options.onCaughtUp == nil
+
+		var (
+			pending      []byte           // pending rollback held for debouncing
+			pendingPoint chainsync.Point  // pendingPoint rolled back to by pending
+			pendingAt    time.Time        // pendingAt is when pending was received
+			flush        <-chan time.Time // flush fires once pending has been held for the debounce window
+		)
+		deliver := func() error {
+			if err := callback(ctx, pending); err != nil {
+				return fmt.Errorf("chainsync stopped: callback failed: %w", err)
+			}
+			last.add(pending)
+			pending, flush = nil, nil
+			return nil
+		}
+		// save stores the last point processed; while a rollback is held, the points before it
+		// may have been rolled back, so the point rolled back to is stored instead.  Resuming
+		// from it, ogmios first rolls back to that point.
+		save := func() error {
+			point, ok := pendingPoint, pending != nil
+			if !ok {
+				point, ok = getPoint(last.list()...)
+			}
+			if ok {
+				if err := options.store.Save(context.Background(), point); err != nil {
+					return fmt.Errorf("chainsync client failed: %w", err)
+				}
+			}
+			return nil
+		}
+
+		var n uint64
+		for {
+			var f frame
+			select {
+			case <-ctx.Done():
+				return save()
+			case <-flush:
+				if err := deliver(); err != nil {
+					return err
+				}
+				continue
+			case v, ok := <-frames:
+				if !ok {
+					return nil
+				}
+				f = v
+			}
+			messageType, data := f.messageType, f.data
+			n++
+
+			select {
 			case ch <- struct{}{}:
 				// request the next message
 			default:
@@ -260,12 +665,7 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 				continue
 
 			case websocket.CloseMessage:
-				if point, ok := getPoint(last.list()...); ok {
-					if err := options.store.Save(context.Background(), point); err != nil {
-						return fmt.Errorf("chainsync client failed: %w", err)
-					}
-				}
-				return nil
+				return save()
 
 			case websocket.PingMessage:
 				if err := conn.WriteMessage(websocket.PongMessage, nil); err != nil {
@@ -292,10 +692,50 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 				}
 			}
 
+			if options.rollbackDebounce > 0 {
+				if point, ok := getRollBackward(data); ok {
+					switch {
+					case pending == nil:
+					case time.Since(pendingAt) > options.rollbackDebounce:
+						if err := deliver(); err != nil {
+							return err
+						}
+					case isDeeper(point, pendingPoint):
+						pending, pendingPoint = data, point
+						continue
+					default:
+						continue
+					}
+					pending, pendingPoint, pendingAt = data, point, time.Now()
+					flush = time.After(options.rollbackDebounce)
+					continue
+				}
+
+				if pending != nil {
+					if err := deliver(); err != nil {
+						return err
+					}
+				}
+			}
+
 			if err := callback(ctx, data); err != nil {
 				return fmt.Errorf("chainsync stopped: callback failed: %w", err)
 			}
 
+			if !caughtUp {
+				if processed, tip, ok := getProgress(data); ok && processed.Slot == tip.Slot && processed.Hash == tip.Hash {
+					options.onCaughtUp()
+					caughtUp = true
+				}
+			}
+
+			if options.progress != nil && time.Since(lastProgress) >= options.progressInterval {
+				if processed, tip, ok := getProgress(data); ok {
+					options.progress(processed, tip)
+					lastProgress = time.Now()
+				}
+			}
+
 			// periodically save points to the store to allow graceful recovery
 			if n%c.options.saveInterval == 0 {
 				if point, ok := getPoint(last.prefix(data)...); ok {
@@ -310,7 +750,9 @@ func (c *Client) doChainSync(ctx context.Context, callback ChainSyncFunc, option
 	return group.Wait()
 }
 
-func getInit(ctx context.Context, store Store, pp ...chainsync.Point) (data []byte, err error) {
+// getInit returns the FindIntersect request for the points loaded from the store, falling
+// back to pp; points are sorted newest first, deduplicated, and capped at limit
+func getInit(ctx context.Context, store Store, limit int, pp ...chainsync.Point) (Map, error) {
 	points, err := store.Load(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve points from store: %w", err)
@@ -322,19 +764,12 @@ func getInit(ctx context.Context, store Store, pp ...chainsync.Point) (data []by
 		points = append(points, chainsync.Origin)
 	}
 	sort.Sort(points)
-	if len(points) > 5 {
-		points = points[0:5]
+	points = points.Dedup()
+	if len(points) > limit {
+		points = points[0:limit]
 	}
 
-	init := Map{
-		"type":        "jsonwsp/request",
-		"version":     "1.0",
-		"servicename": "ogmios",
-		"methodname":  "FindIntersect",
-		"args":        Map{"points": points},
-		"mirror":      Map{"step": "INIT"},
-	}
-	return json.Marshal(init)
+	return makePayload("FindIntersect", Map{"points": points}), nil
 }
 
 // getPoint returns the first point from the list of json encoded chainsync.Responses provided
@@ -356,6 +791,200 @@ func getPoint(data ...[]byte) (chainsync.Point, bool) {
 	return chainsync.Point{}, false
 }
 
+// getRollBackward returns the point rolled back to if the json encoded chainsync.Response
+// is a RollBackward
+func getRollBackward(data []byte) (chainsync.Point, bool) {
+	if !bytes.Contains(data, []byte(`"RollBackward"`)) {
+		return chainsync.Point{}, false
+	}
+
+	var response chainsync.Response
+	if err := json.Unmarshal(data, &response); err != nil {
+		return chainsync.Point{}, false
+	}
+	if response.Result == nil || response.Result.RollBackward == nil {
+		return chainsync.Point{}, false
+	}
+	return response.Result.RollBackward.Point, true
+}
+
+// rollbackOnly adapts fn to a ChainSyncFunc that ignores all but RollBackward messages
+func rollbackOnly(fn RollbackFunc) ChainSyncFunc {
+	return func(ctx context.Context, data []byte) error {
+		if !bytes.Contains(data, []byte(`"RollBackward"`)) {
+			return nil
+		}
+
+		var response chainsync.Response
+		if err := json.Unmarshal(data, &response); err != nil {
+			return fmt.Errorf("failed to decode RollBackward: %w", err)
+		}
+		if response.Result == nil || response.Result.RollBackward == nil {
+			return nil
+		}
+
+		var tip chainsync.PointStruct
+		if ps, ok := response.Result.RollBackward.Tip.PointStruct(); ok {
+			tip = *ps
+		}
+		return fn(ctx, response.Result.RollBackward.Point, tip)
+	}
+}
+
+// eraTransition wraps callback, invoking fn whenever the era of consecutive blocks differs
+func eraTransition(callback ChainSyncFunc, fn EraTransitionFunc) ChainSyncFunc {
+	var era chainsync.Era
+	return func(ctx context.Context, data []byte) error {
+		if next, ok := getEra(data); ok && next != era {
+			if era != (chainsync.Era{}) {
+				var response chainsync.Response
+				if err := json.Unmarshal(data, &response); err != nil {
+					return fmt.Errorf("failed to decode RollForward: %w", err)
+				}
+				fn(era, next, response.Result.RollForward.Block.PointStruct())
+			}
+			era = next
+		}
+		return callback(ctx, data)
+	}
+}
+
+// networkCheck fails with chainsync.ErrNetworkMismatch if a block rolled forward contains a
+// transaction belonging to a network other than network
+func networkCheck(callback ChainSyncFunc, network chainsync.Network) ChainSyncFunc {
+	return func(ctx context.Context, data []byte) error {
+		var response chainsync.Response
+		if err := json.Unmarshal(data, &response); err == nil && response.Result != nil && response.Result.RollForward != nil {
+			if block := shelleyBlock(response.Result.RollForward.Block); block != nil {
+				for _, tx := range block.Body {
+					if err := tx.CheckNetwork(network); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return callback(ctx, data)
+	}
+}
+
+// confirmed delays RollForward messages until their blocks are depth blocks below the tip
+type confirmed struct {
+	callback  ChainSyncFunc
+	depth     uint64
+	pending   [][]byte                // pending RollForward messages awaiting confirmation
+	points    []chainsync.PointStruct // points of the pending blocks
+	delivered chainsync.Point         // delivered is the point of the last block released
+}
+
+func (c *confirmed) chainSyncFunc(ctx context.Context, data []byte) error {
+	if point, ok := getRollBackward(data); ok {
+		ps, isBlock := point.PointStruct()
+		for n := len(c.points); n > 0 && (!isBlock || c.points[n-1].Slot > ps.Slot); n-- {
+			c.pending, c.points = c.pending[:n-1], c.points[:n-1]
+		}
+		if delivered, ok := c.delivered.PointStruct(); ok && (!isBlock || delivered.Slot > ps.Slot) {
+			c.delivered = point
+			return c.callback(ctx, data)
+		}
+		return nil // only buffered blocks were rolled back
+	}
+
+	var response chainsync.Response
+	if err := json.Unmarshal(data, &response); err != nil || response.Result == nil || response.Result.RollForward == nil {
+		return c.callback(ctx, data)
+	}
+
+	c.pending = append(c.pending, data)
+	c.points = append(c.points, response.Result.RollForward.Block.PointStruct())
+
+	var tip uint64
+	if ps, ok := response.Result.RollForward.Tip.PointStruct(); ok {
+		tip = ps.BlockNo
+	}
+	for len(c.points) > 0 && (uint64(len(c.points)) > c.depth || c.points[0].BlockNo+c.depth <= tip) {
+		if err := c.callback(ctx, c.pending[0]); err != nil {
+			return err
+		}
+		c.delivered = c.points[0].Point()
+		c.pending, c.points = c.pending[1:], c.points[1:]
+	}
+	return nil
+}
+
+// confirmedStore saves the last block delivered by confirmed in place of the last block
+// received, which may still be buffered
+type confirmedStore struct {
+	Store
+	confirmed *confirmed
+}
+
+func (s confirmedStore) Save(ctx context.Context, _ chainsync.Point) error {
+	if s.confirmed.delivered.PointType() == 0 {
+		return nil // nothing delivered
+	}
+	return s.Store.Save(ctx, s.confirmed.delivered)
+}
+
+// errStopIteration halts jsonparser iteration once the desired key is found
+var errStopIteration = errors.New("stop iteration")
+
+// getEra returns the era of the block if the json encoded chainsync.Response is a RollForward
+func getEra(data []byte) (era chainsync.Era, ok bool) {
+	_ = jsonparser.ObjectEach(data, func(key []byte, _ []byte, _ jsonparser.ValueType, _ int) error {
+		for _, e := range chainsync.Eras {
+			if e.String() == string(key) {
+				era, ok = e, true
+			}
+		}
+		return errStopIteration
+	}, "result", "RollForward", "block")
+	return era, ok
+}
+
+// isDeeper returns true if rolling back to point a discards more of the chain than
+// rolling back to point b
+func isDeeper(a, b chainsync.Point) bool {
+	pa, okA := a.PointStruct()
+	pb, okB := b.PointStruct()
+	switch {
+	case !okA:
+		return okB // origin
+	case !okB:
+		return false
+	default:
+		return pa.Slot < pb.Slot
+	}
+}
+
+// getProgress returns the point processed by the json encoded chainsync.Response along
+// with the tip ogmios reported alongside it
+func getProgress(data []byte) (processed, tip chainsync.PointStruct, ok bool) {
+	var response chainsync.Response
+	if err := json.Unmarshal(data, &response); err != nil || response.Result == nil {
+		return chainsync.PointStruct{}, chainsync.PointStruct{}, false
+	}
+
+	var point, tipPoint chainsync.Point
+	switch {
+	case response.Result.RollForward != nil:
+		point = response.Result.RollForward.Block.PointStruct().Point()
+		tipPoint = response.Result.RollForward.Tip
+	case response.Result.RollBackward != nil:
+		point = response.Result.RollBackward.Point
+		tipPoint = response.Result.RollBackward.Tip
+	default:
+		return chainsync.PointStruct{}, chainsync.PointStruct{}, false
+	}
+
+	if ps, ok := point.PointStruct(); ok {
+		processed = *ps
+	}
+	if ps, ok := tipPoint.PointStruct(); ok {
+		tip = *ps
+	}
+	return processed, tip, true
+}
+
 // isTemporaryError returns true if the error is recoverable
 func isTemporaryError(err error) bool {
 	wce := &websocket.CloseError{}
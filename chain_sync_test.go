@@ -15,16 +15,26 @@
 package ogmigo
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
@@ -124,27 +134,834 @@ func Test_getInit(t *testing.T) {
 		store := mockStore{
 			pp: chainsync.Points{p1.Point()},
 		}
-		points, err := getInit(ctx, store, p2.Point())
+		points, err := getInit(ctx, store, 5, p2.Point())
 		if err != nil {
 			t.Fatalf("got %v; want nil", err)
 		}
 
-		want := `{"args":{"points":[{"blockNo":123,"hash":"hash","slot":456}]},"methodname":"FindIntersect","mirror":{"step":"INIT"},"servicename":"ogmios","type":"jsonwsp/request","version":"1.0"}`
-		if got := string(points); got != want {
+		want := `{"args":{"points":[{"blockNo":123,"hash":"hash","slot":456}]},"methodname":"FindIntersect","servicename":"ogmios","type":"jsonwsp/request","version":"1.0"}`
+		if got := string(mustMarshal(t, points)); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("from origin", func(t *testing.T) {
+		options := buildChainSyncOptions(WithStartFromOrigin())
+		points, err := getInit(ctx, options.store, options.maxIntersectPoints, options.points...)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		want := `{"args":{"points":["origin"]},"methodname":"FindIntersect","servicename":"ogmios","type":"jsonwsp/request","version":"1.0"}`
+		if got := string(mustMarshal(t, points)); got != want {
 			t.Fatalf("got %v; want %v", got, want)
 		}
 	})
 
 	t.Run("from points", func(t *testing.T) {
 		store := mockStore{}
-		points, err := getInit(ctx, store, p1.Point())
+		points, err := getInit(ctx, store, 5, p1.Point())
 		if err != nil {
 			t.Fatalf("got %v; want nil", err)
 		}
 
-		want := `{"args":{"points":[{"blockNo":123,"hash":"hash","slot":456}]},"methodname":"FindIntersect","mirror":{"step":"INIT"},"servicename":"ogmios","type":"jsonwsp/request","version":"1.0"}`
-		if got := string(points); got != want {
+		want := `{"args":{"points":[{"blockNo":123,"hash":"hash","slot":456}]},"methodname":"FindIntersect","servicename":"ogmios","type":"jsonwsp/request","version":"1.0"}`
+		if got := string(mustMarshal(t, points)); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+	t.Run("sloppy store", func(t *testing.T) {
+		store := mockStore{
+			pp: chainsync.Points{p1.Point(), p2.Point(), p1.Point(), chainsync.Origin, p2.Point()},
+		}
+		points, err := getInit(ctx, store, 2)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		want := `{"args":{"points":[{"blockNo":321,"hash":"hash","slot":654},{"blockNo":123,"hash":"hash","slot":456}]},"methodname":"FindIntersect","servicename":"ogmios","type":"jsonwsp/request","version":"1.0"}`
+		if got := string(mustMarshal(t, points)); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+}
+
+func Test_getProgress(t *testing.T) {
+	t.Run("roll forward", func(t *testing.T) {
+		data := []byte(`{"result":{"RollForward":{"block":{"babbage":{"header":{"blockHeight":10,"slot":100},"headerHash":"block"}},"tip":{"slot":200,"hash":"tip","blockNo":20}}}}`)
+		processed, tip, ok := getProgress(data)
+		if !ok {
+			t.Fatalf("got false; want true")
+		}
+		if got, want := processed, (chainsync.PointStruct{BlockNo: 10, Hash: "block", Slot: 100}); got != want {
+			t.Fatalf("got %#v; want %#v", got, want)
+		}
+		if got, want := tip, (chainsync.PointStruct{BlockNo: 20, Hash: "tip", Slot: 200}); got != want {
+			t.Fatalf("got %#v; want %#v", got, want)
+		}
+	})
+
+	t.Run("roll backward", func(t *testing.T) {
+		data := []byte(`{"result":{"RollBackward":{"point":{"slot":100,"hash":"block"},"tip":{"slot":200,"hash":"tip","blockNo":20}}}}`)
+		processed, tip, ok := getProgress(data)
+		if !ok {
+			t.Fatalf("got false; want true")
+		}
+		if got, want := processed.Slot, uint64(100); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := tip.Slot, uint64(200); got != want {
 			t.Fatalf("got %v; want %v", got, want)
 		}
 	})
+
+	t.Run("intersection", func(t *testing.T) {
+		data := []byte(`{"result":{"IntersectionFound":{"point":"origin","tip":{"slot":200,"hash":"tip","blockNo":20}}}}`)
+		if _, _, ok := getProgress(data); ok {
+			t.Fatalf("got true; want false")
+		}
+	})
+}
+
+// chainSyncServer replies to FindIntersect with an intersection at origin and
+// to each RequestNext with the next of the provided responses
+func chainSyncServer(t *testing.T, responses ...string) string {
+	var upgrader = websocket.Upgrader{}
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		c, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		next := 0
+		for {
+			_, message, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var reply string
+			switch {
+			case bytes.Contains(message, []byte("FindIntersect")):
+				reply = `{"type":"jsonwsp/response","methodname":"FindIntersect","result":{"IntersectionFound":{"point":"origin","tip":"origin"}}}`
+			case next < len(responses):
+				reply = responses[next]
+				next++
+			default:
+				continue
+			}
+
+			if err := c.WriteMessage(websocket.TextMessage, []byte(reply)); err != nil {
+				return
+			}
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func rollForward(slot, tip uint64) string {
+	return fmt.Sprintf(`{"type":"jsonwsp/response","methodname":"RequestNext","result":{"RollForward":{"block":{"babbage":{"header":{"blockHeight":%v,"slot":%v},"headerHash":"%v"}},"tip":{"slot":%v,"hash":"%v","blockNo":%v}}}}`, slot, slot, slot, tip, tip, tip)
+}
+
+func TestClient_ChainSyncOnCaughtUp(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(1, 3),
+		rollForward(2, 3),
+		rollForward(3, 3),
+		rollForward(4, 4),
+	)
+
+	var (
+		ctx      = context.Background()
+		received int64
+		caughtUp = make(chan int64, 2)
+	)
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		atomic.AddInt64(&received, 1)
+		return nil
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	closer, err := client.ChainSync(ctx, callback,
+		WithOnCaughtUp(func() { caughtUp <- atomic.LoadInt64(&received) }),
+	)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer closer.Close()
+
+	select {
+	case got := <-caughtUp:
+		// intersection + 3 blocks
+		if want := int64(4); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for caught up notification")
+	}
+
+	select {
+	case <-caughtUp:
+		t.Fatalf("got second caught up notification; want one")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestClient_ChainSyncFrameTap(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(1, 2),
+		rollForward(2, 2),
+	)
+
+	var (
+		mutex  sync.Mutex
+		frames = map[string][]string{}
+		done   = make(chan struct{})
+	)
+	tap := func(direction string, frame []byte) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		frames[direction] = append(frames[direction], string(frame))
+	}
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		if point, ok := getPoint(data); ok {
+			if ps, _ := point.PointStruct(); ps.Slot == 2 {
+				close(done)
+			}
+		}
+		return nil
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithFrameTap(tap))
+	closer, err := client.ChainSync(context.Background(), callback)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer closer.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for blocks")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	// intersection + 2 blocks
+	if got, want := len(frames[FrameInbound]), 3; got < want {
+		t.Fatalf("got %v; want at least %v", got, want)
+	}
+	if got, want := frames[FrameInbound][2], rollForward(2, 2); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := frames[FrameOutbound][0], `"FindIntersect"`; !strings.Contains(got, want) {
+		t.Fatalf("got %v; want contains %v", got, want)
+	}
+}
+
+func TestClient_ChainSyncProgressInterval(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(1, 3),
+		rollForward(2, 3),
+		rollForward(3, 3),
+	)
+
+	progress := make(chan uint64, 3)
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		return nil
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	closer, err := client.ChainSync(context.Background(), callback,
+		WithProgress(func(processed, tip chainsync.PointStruct) { progress <- processed.Slot }),
+		WithProgressInterval(time.Nanosecond),
+	)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer closer.Close()
+
+	for want := uint64(1); want <= 3; want++ {
+		select {
+		case got := <-progress:
+			if got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for progress at slot %v", want)
+		}
+	}
+}
+
+func TestClient_ChainSyncIDGenerator(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(1, 2),
+		rollForward(2, 2),
+	)
+
+	var (
+		mutex  sync.Mutex
+		frames []string
+		n      int64
+		done   = make(chan struct{})
+	)
+	tap := func(direction string, frame []byte) {
+		if direction != FrameOutbound {
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		frames = append(frames, string(frame))
+	}
+	id := func() json.RawMessage {
+		return json.RawMessage(fmt.Sprintf(`"id-%v"`, atomic.AddInt64(&n, 1)))
+	}
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		if point, ok := getPoint(data); ok {
+			if ps, _ := point.PointStruct(); ps.Slot == 2 {
+				close(done)
+			}
+		}
+		return nil
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithFrameTap(tap), WithIDGenerator(id))
+	closer, err := client.ChainSync(context.Background(), callback)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer closer.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for blocks")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if got, want := len(frames), 3; got < want {
+		t.Fatalf("got %v; want at least %v", got, want)
+	}
+	for i, frame := range frames[:3] {
+		method := "RequestNext"
+		if i == 0 {
+			method = "FindIntersect"
+		}
+		if got, want := frame, `"methodname":"`+method+`"`; !strings.Contains(got, want) {
+			t.Fatalf("got %v; want contains %v", got, want)
+		}
+		if got, want := frame, fmt.Sprintf(`"mirror":{"id":"id-%v"}`, i+1); !strings.Contains(got, want) {
+			t.Fatalf("got %v; want contains %v", got, want)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return data
+}
+
+func rollBackward(slot, tip uint64) string {
+	return fmt.Sprintf(`{"type":"jsonwsp/response","methodname":"RequestNext","result":{"RollBackward":{"point":{"slot":%v,"hash":"%v"},"tip":{"slot":%v,"hash":"%v","blockNo":%v}}}}`, slot, slot, tip, tip, tip)
+}
+
+type recordingStore struct {
+	mockStore
+	saved *chainsync.Points
+}
+
+func (r recordingStore) Save(_ context.Context, p chainsync.Point) error {
+	*r.saved = append(*r.saved, p)
+	return nil
+}
+
+func Test_confirmed(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		received []string
+	)
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		if point, ok := getRollBackward(data); ok {
+			ps, _ := point.PointStruct()
+			received = append(received, fmt.Sprintf("B%v", ps.Slot))
+		} else if point, ok := getPoint(data); ok {
+			ps, _ := point.PointStruct()
+			received = append(received, fmt.Sprintf("F%v", ps.Slot))
+		}
+		return nil
+	}
+
+	var (
+		c     = &confirmed{callback: callback, depth: 2}
+		saved chainsync.Points
+		store = confirmedStore{Store: recordingStore{saved: &saved}, confirmed: c}
+	)
+	if err := store.Save(ctx, chainsync.Origin); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got := len(saved); got != 0 {
+		t.Fatalf("got %v; want 0 saves before delivery", got)
+	}
+
+	messages := []string{
+		rollForward(1, 3),  // tip is 2 blocks ahead: delivered
+		rollForward(2, 3),  // buffered
+		rollForward(3, 3),  // buffered
+		rollForward(4, 4),  // delivers 2
+		rollBackward(3, 4), // discards buffered 4
+		rollForward(4, 6),  // delivers 3 and 4 via tip
+		rollBackward(1, 6), // discards delivered 2, 3, and 4
+		rollForward(2, 2),  // buffered
+	}
+	for _, message := range messages {
+		if err := c.chainSyncFunc(ctx, []byte(message)); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	}
+
+	want := []string{"F1", "F2", "F3", "F4", "B1"}
+	if !reflect.DeepEqual(received, want) {
+		t.Fatalf("got %v; want %v", received, want)
+	}
+
+	if err := store.Save(ctx, chainsync.Origin); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if ps, ok := saved[0].PointStruct(); !ok || ps.Slot != 1 {
+		t.Fatalf("got %v; want slot 1", saved[0])
+	}
+}
+
+func TestClient_ChainSyncRollbackDebounce(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(5, 6),
+		rollForward(6, 6),
+		rollBackward(5, 6),
+		rollBackward(3, 6),
+		rollBackward(4, 6),
+		rollForward(4, 6),
+	)
+
+	var (
+		ctx      = context.Background()
+		received = make(chan string, 16)
+	)
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		var response chainsync.Response
+		if err := json.Unmarshal(data, &response); err != nil {
+			return err
+		}
+		switch {
+		case response.Result.RollForward != nil:
+			received <- fmt.Sprintf("forward %v", response.Result.RollForward.Block.PointStruct().Slot)
+		case response.Result.RollBackward != nil:
+			received <- fmt.Sprintf("backward %v", response.Result.RollBackward.Point)
+		}
+		return nil
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	closer, err := client.ChainSync(ctx, callback, WithRollbackDebounce(time.Minute))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer closer.Close()
+
+	want := []string{
+		"forward 5",
+		"forward 6",
+		"backward slot=3 hash=3",
+		"forward 4",
+	}
+	for _, w := range want {
+		select {
+		case got := <-received:
+			if got != w {
+				t.Fatalf("got %v; want %v", got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %v", w)
+		}
+	}
+}
+
+func TestClient_ChainSyncRollbackDebounceFlush(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(5, 6),
+		rollForward(6, 6),
+		rollBackward(5, 6),
+		rollBackward(3, 6),
+	)
+
+	received := make(chan string, 16)
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		if point, ok := getRollBackward(data); ok {
+			received <- point.String()
+		}
+		return nil
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	closer, err := client.ChainSync(context.Background(), callback, WithRollbackDebounce(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer closer.Close()
+
+	// no message follows the rollbacks, so the deepest is delivered once the window passes
+	select {
+	case got := <-received:
+		if want := "slot=3 hash=3"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for held rollback")
+	}
+}
+
+func TestClient_ChainSyncRollbackDebounceSave(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(5, 6),
+		rollForward(6, 6),
+		rollBackward(3, 6),
+	)
+
+	received := make(chan struct{}, 1)
+	tap := func(direction string, frame []byte) {
+		if _, ok := getRollBackward(frame); ok {
+			received <- struct{}{}
+		}
+	}
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		return nil
+	}
+
+	store := NewFileStore(filepath.Join(t.TempDir(), "points.json"), 5)
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithFrameTap(tap))
+	closer, err := client.ChainSync(context.Background(), callback,
+		WithRollbackDebounce(time.Minute),
+		WithStore(store),
+	)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for rollback")
+	}
+	time.Sleep(100 * time.Millisecond) // allow the rollback to be held
+	if err := closer.Close(); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	select {
+	case <-closer.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for chainsync to stop")
+	}
+
+	// the held rollback was never delivered, so the point rolled back to is saved
+	points, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(points), 1; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := points[0].String(), "slot=3 hash=3"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestClient_ChainSyncRollbackOnly(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(5, 6),
+		rollForward(6, 6),
+		rollBackward(5, 6),
+		rollForward(6, 7),
+	)
+
+	var (
+		ctx      = context.Background()
+		received = make(chan string, 16)
+	)
+	rollback := func(ctx context.Context, point chainsync.Point, tip chainsync.PointStruct) error {
+		received <- fmt.Sprintf("backward %v tip %v", point, tip.Slot)
+		return nil
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	closer, err := client.ChainSync(ctx, nil, WithRollbackOnly(rollback))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer closer.Close()
+
+	select {
+	case got := <-received:
+		if want := "backward slot=5 hash=5 tip 6"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for rollback")
+	}
+
+	select {
+	case got := <-received:
+		t.Fatalf("got %v; want no further callbacks", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestClient_ChainSyncOnEraTransition(t *testing.T) {
+	alonzo := strings.Replace(rollForward(4, 6), `"babbage"`, `"alonzo"`, 1)
+	endpoint := chainSyncServer(t,
+		alonzo,
+		rollForward(5, 6),
+		rollForward(6, 6),
+	)
+
+	var (
+		ctx         = context.Background()
+		transitions = make(chan string, 16)
+		received    = make(chan struct{}, 16)
+	)
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		received <- struct{}{}
+		return nil
+	}
+	onEraTransition := func(from, to chainsync.Era, at chainsync.PointStruct) {
+		transitions <- fmt.Sprintf("%v -> %v at %v", from, to, at.Slot)
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	closer, err := client.ChainSync(ctx, callback, WithOnEraTransition(onEraTransition))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer closer.Close()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for block %v", i)
+		}
+	}
+
+	select {
+	case got := <-transitions:
+		if want := "alonzo -> babbage at 5"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	default:
+		t.Fatalf("got no transition; want alonzo -> babbage")
+	}
+	if got, want := len(transitions), 0; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func Test_getEra(t *testing.T) {
+	era, ok := getEra([]byte(rollForward(5, 6)))
+	if !ok || era != chainsync.Babbage {
+		t.Fatalf("got %v, %v; want babbage, true", era, ok)
+	}
+	if _, ok := getEra([]byte(rollBackward(5, 6))); ok {
+		t.Fatalf("got true; want false")
+	}
+}
+
+func TestClient_SyncRange(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(1, 5),
+		rollForward(2, 5),
+		rollForward(3, 5),
+		rollForward(4, 5),
+		rollForward(5, 5),
+	)
+
+	var slots []uint64
+	var callback ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		var response chainsync.Response
+		if err := json.Unmarshal(data, &response); err != nil {
+			return err
+		}
+		if response.Result != nil && response.Result.RollForward != nil {
+			slots = append(slots, response.Result.RollForward.Block.PointStruct().Slot)
+		}
+		return nil
+	}
+
+	var (
+		ctx    = context.Background()
+		client = New(WithEndpoint(endpoint), WithLogger(NopLogger))
+		from   = chainsync.PointStruct{Slot: 1, Hash: "1"}.Point()
+		to     = chainsync.PointStruct{Slot: 3, Hash: "3"}.Point()
+	)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := client.SyncRange(ctx, from, to, callback); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := slots, []uint64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if err := client.SyncRange(ctx, from, chainsync.Origin, callback); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func Test_isDeeper(t *testing.T) {
+	p3 := chainsync.PointStruct{Slot: 3}.Point()
+	p5 := chainsync.PointStruct{Slot: 5}.Point()
+
+	if !isDeeper(p3, p5) {
+		t.Fatalf("got false; want true")
+	}
+	if isDeeper(p5, p3) {
+		t.Fatalf("got true; want false")
+	}
+	if !isDeeper(chainsync.Origin, p3) {
+		t.Fatalf("got false; want true")
+	}
+	if isDeeper(p3, chainsync.Origin) {
+		t.Fatalf("got true; want false")
+	}
+}
+
+func TestClient_DumpChain(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(1, 2),
+		rollBackward(0, 2),
+		rollForward(2, 2),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	errs := make(chan error, 1)
+	go func() {
+		errs <- client.DumpChain(ctx, pw)
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	for _, want := range []string{`{"RollForward":`, `{"RollBackward":`, `{"RollForward":`} {
+		if !scanner.Scan() {
+			t.Fatalf("got %v; want line", scanner.Err())
+		}
+		var result chainsync.Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got := scanner.Text(); !strings.HasPrefix(got, want) {
+			t.Fatalf("got %v; want prefix %v", got, want)
+		}
+	}
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+}
+
+func TestTypedChainSyncFunc(t *testing.T) {
+	data := []byte(rollForward(5, 6))
+
+	var (
+		gotData     []byte
+		gotResponse chainsync.Response
+	)
+	callback := TypedChainSyncFunc(func(ctx context.Context, data []byte, response chainsync.Response) error {
+		gotData, gotResponse = data, response
+		return nil
+	})
+
+	if err := callback(context.Background(), data); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if !bytes.Equal(gotData, data) {
+		t.Fatalf("got %v; want %v", string(gotData), string(data))
+	}
+	if got, want := gotResponse.Result.RollForward.Block.PointStruct().Slot, uint64(5); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if err := callback(context.Background(), []byte(`{`)); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func Test_networkCheck(t *testing.T) {
+	var delivered int
+	callback := networkCheck(func(ctx context.Context, data []byte) error {
+		delivered++
+		return nil
+	}, chainsync.NetworkMainnet)
+
+	message := func(address string) []byte {
+		return []byte(`{"type":"jsonwsp/response","result":{"RollForward":{"block":{"babbage":{"header":{"slot":5},"headerHash":"abc","body":[{"id":"tx","body":{"outputs":[{"address":"` +
+			address + `"}]}}]}},"tip":{"slot":5,"hash":"abc","blockNo":5}}}}`)
+	}
+
+	ctx := context.Background()
+	if err := callback(ctx, message("addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8")); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := callback(ctx, []byte(rollBackward(1, 6))); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	err := callback(ctx, message("addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz"))
+	if !errors.Is(err, chainsync.ErrNetworkMismatch) {
+		t.Fatalf("got %v; want %v", err, chainsync.ErrNetworkMismatch)
+	}
+	if got, want := delivered, 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestClient_CollectBlocks(t *testing.T) {
+	endpoint := chainSyncServer(t,
+		rollForward(1, 5),
+		rollForward(2, 5),
+		rollBackward(1, 5),
+		rollForward(3, 5),
+		rollForward(4, 5),
+		rollForward(5, 5),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	blocks, err := client.CollectBlocks(ctx, 3)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var slots []uint64
+	for _, block := range blocks {
+		slots = append(slots, block.Header.Slot)
+	}
+	if got, want := slots, []uint64{1, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
 }
@@ -14,15 +14,29 @@
 
 package ogmigo
 
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
 // Client provides a client for the chain sync protocol only
 type Client struct {
 	logger  Logger
 	options Options
+
+	mutex        sync.Mutex  // mutex guards the cached era history
+	eraHistory   *EraHistory // eraHistory cached by CachedEraHistory
+	eraHistoryAt time.Time   // eraHistoryAt is when eraHistory was retrieved
 }
 
-// New returns a new Client
+// New returns a new Client.  New panics if the endpoint is malformed; http and https
+// endpoints are accepted and normalized to ws and wss respectively.
 func New(opts ...Option) *Client {
 	options := buildOptions(opts...)
+	options.endpoint = mustNormalizeEndpoint(options.endpoint)
 	logger := options.logger.With(KV("service", "ogmios"))
 
 	return &Client{
@@ -30,3 +44,47 @@ func New(opts ...Option) *Client {
 		options: options,
 	}
 }
+
+// WithDefaults returns a copy of the Client with the provided options applied
+// on top of the options the Client was created with
+func (c *Client) WithDefaults(opts ...Option) *Client {
+	options := c.options
+	for _, opt := range opts {
+		opt(&options)
+	}
+	options.endpoint = mustNormalizeEndpoint(options.endpoint)
+
+	return &Client{
+		logger:  options.logger.With(KV("service", "ogmios")),
+		options: options,
+	}
+}
+
+// normalizeEndpoint validates the endpoint and converts http schemes to their websocket equivalent
+func normalizeEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "ws", "http":
+		u.Scheme = "ws"
+	case "wss", "https":
+		u.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("unsupported scheme, %q: want ws, wss, http, or https", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("missing host")
+	}
+	return u.String(), nil
+}
+
+func mustNormalizeEndpoint(endpoint string) string {
+	normalized, err := normalizeEndpoint(endpoint)
+	if err != nil {
+		panic(fmt.Sprintf("ogmigo: invalid endpoint, %v: %v", endpoint, err))
+	}
+	return normalized
+}
@@ -14,6 +14,76 @@
 
 package ogmigo
 
+import (
+	"testing"
+)
+
+func TestClient_WithDefaults(t *testing.T) {
+	client := New(WithEndpoint("ws://example.com:1337"), WithPipeline(10))
+	derived := client.WithDefaults(WithPipeline(20), WithLogger(NopLogger))
+
+	if got, want := derived.options.endpoint, "ws://example.com:1337"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := derived.options.pipeline, 20; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := derived.logger, Logger(NopLogger); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := client.options.pipeline, 10; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	// a nil dialer keeps the default rather than leaving queries to dereference nil
+	if got := client.WithDefaults(WithDialer(nil)).options.websocketDialer(); got == nil {
+		t.Fatalf("got nil; want dialer")
+	}
+}
+
+func Test_normalizeEndpoint(t *testing.T) {
+	tests := map[string]struct {
+		Endpoint string
+		Want     string
+		WantErr  bool
+	}{
+		"ws":      {Endpoint: "ws://127.0.0.1:1337", Want: "ws://127.0.0.1:1337"},
+		"wss":     {Endpoint: "wss://ogmios.example.com", Want: "wss://ogmios.example.com"},
+		"http":    {Endpoint: "http://127.0.0.1:1337", Want: "ws://127.0.0.1:1337"},
+		"https":   {Endpoint: "HTTPS://ogmios.example.com/path", Want: "wss://ogmios.example.com/path"},
+		"scheme":  {Endpoint: "tcp://127.0.0.1:1337", WantErr: true},
+		"no host": {Endpoint: "127.0.0.1:1337", WantErr: true},
+		"garbage": {Endpoint: "ws://[::1", WantErr: true},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			got, err := normalizeEndpoint(tc.Endpoint)
+			if tc.WantErr {
+				if err == nil {
+					t.Fatalf("got nil; want err")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidEndpoint(t *testing.T) {
+	defer func() {
+		if v := recover(); v == nil {
+			t.Fatalf("got nil; want panic")
+		}
+	}()
+	New(WithEndpoint("127.0.0.1:1337"))
+}
+
 //func TestClient_ReadNext(t *testing.T) {
 //	endpoint := os.Getenv("OGMIOS")
 //	if endpoint == "" {
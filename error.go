@@ -15,9 +15,35 @@
 package ogmigo
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+
+	"github.com/buger/jsonparser"
 )
 
+// ErrOgmiosUnreachable is matched, via errors.Is, by errors connecting to ogmios; use
+// errors.As with *UnreachableError to retrieve the endpoint
+var ErrOgmiosUnreachable = errors.New("ogmios unreachable")
+
+// UnreachableError indicates a connection to ogmios could not be established, allowing
+// a node being down to be distinguished from protocol errors
+type UnreachableError struct {
+	Endpoint string // Endpoint dialed
+	Err      error  // Err returned by the dialer
+}
+
+// Error implements error interface
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("failed to connect to ogmios, %v: %v", e.Endpoint, e.Err)
+}
+
+// Is matches ErrOgmiosUnreachable
+func (e *UnreachableError) Is(target error) bool { return target == ErrOgmiosUnreachable }
+
+// Unwrap returns the underlying dial error
+func (e *UnreachableError) Unwrap() error { return e.Err }
+
 // Error encapsulates errors from ogmios
 type Error struct {
 	Type        string `json:"type,omitempty"`
@@ -34,3 +60,46 @@ type Fault struct {
 	Code   string `json:"code,omitempty"`   // Code identifies error
 	String string `json:"string,omitempty"` // String provides human readable description
 }
+
+// EraMismatchError is returned by queries that are unavailable in the era of the ledger
+type EraMismatchError struct {
+	LedgerEra string `json:"ledgerEra,omitempty"` // LedgerEra of the node
+	QueryEra  string `json:"queryEra,omitempty"`  // QueryEra the query requires; empty if ogmios did not report it
+}
+
+// Error implements error interface
+func (e EraMismatchError) Error() string {
+	if e.QueryEra == "" {
+		return "query unavailable in current era"
+	}
+	return fmt.Sprintf("era mismatch: ledger is in %v era; query requires %v", e.LedgerEra, e.QueryEra)
+}
+
+// decodeEraMismatch returns the era mismatch reported in the result of a Query response;
+// ogmios v5 reports these as results rather than faults
+func decodeEraMismatch(data []byte) (EraMismatchError, bool) {
+	if method, _ := jsonparser.GetString(data, "methodname"); method != "Query" {
+		return EraMismatchError{}, false
+	}
+	result, dataType, _, err := jsonparser.Get(data, "result")
+	if err != nil {
+		return EraMismatchError{}, false
+	}
+
+	switch dataType {
+	case jsonparser.String:
+		return EraMismatchError{}, string(result) == "QueryUnavailableInCurrentEra"
+	case jsonparser.Object:
+		mismatch, _, _, err := jsonparser.Get(result, "eraMismatch")
+		if err != nil {
+			return EraMismatchError{}, false
+		}
+		var e EraMismatchError
+		if err := json.Unmarshal(mismatch, &e); err != nil {
+			return EraMismatchError{}, false
+		}
+		return e, true
+	default:
+		return EraMismatchError{}, false
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// unreachableEndpoint returns an endpoint on which nothing is listening
+func unreachableEndpoint(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	return "ws://" + addr
+}
+
+func TestUnreachableError(t *testing.T) {
+	endpoint := unreachableEndpoint(t)
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+
+	assertUnreachable := func(t *testing.T, err error) {
+		if !errors.Is(err, ErrOgmiosUnreachable) {
+			t.Fatalf("got %v; want ErrOgmiosUnreachable", err)
+		}
+		var ue *UnreachableError
+		if !errors.As(err, &ue) {
+			t.Fatalf("got %v; want *UnreachableError", err)
+		}
+		if got, want := ue.Endpoint, endpoint; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	}
+
+	t.Run("query", func(t *testing.T) {
+		_, err := client.ChainTip(context.Background())
+		assertUnreachable(t, err)
+	})
+
+	t.Run("chainsync", func(t *testing.T) {
+		closer, err := client.ChainSync(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		select {
+		case <-closer.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for chainsync to fail")
+		}
+		assertUnreachable(t, closer.Close())
+	})
+
+	t.Run("protocol errors", func(t *testing.T) {
+		err := Error{Fault: Fault{Code: "client", String: "invalid query"}}
+		if errors.Is(err, ErrOgmiosUnreachable) {
+			t.Fatalf("got true; want false")
+		}
+	})
+}
+
+func TestEraMismatchError(t *testing.T) {
+	tests := map[string]struct {
+		Result string
+		Want   EraMismatchError
+	}{
+		"era mismatch": {
+			Result: `{"eraMismatch":{"ledgerEra":"Alonzo","queryEra":"Babbage"}}`,
+			Want:   EraMismatchError{LedgerEra: "Alonzo", QueryEra: "Babbage"},
+		},
+		"unavailable": {
+			Result: `"QueryUnavailableInCurrentEra"`,
+			Want:   EraMismatchError{},
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			endpoint, _ := queryServer(t, `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"Query","result":`+tc.Result+`}`)
+
+			client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+			_, err := client.CurrentEpoch(context.Background())
+
+			var got EraMismatchError
+			if !errors.As(err, &got) {
+				t.Fatalf("got %v; want EraMismatchError", err)
+			}
+			if got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+		})
+	}
+
+	t.Run("other results", func(t *testing.T) {
+		if _, ok := decodeEraMismatch([]byte(`{"methodname":"Query","result":{"epoch":1}}`)); ok {
+			t.Fatalf("got true; want false")
+		}
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+// renameFile is swapped in tests to simulate a crash before the write completes
+var renameFile = os.Rename
+
+// FileStore saves the most recent points to a json file; intended for local development
+type FileStore struct {
+	path      string
+	maxPoints int
+	mutex     sync.Mutex
+}
+
+// NewFileStore returns a Store that keeps the most recent maxPoints points in the file at path
+func NewFileStore(path string, maxPoints int) *FileStore {
+	if maxPoints <= 0 {
+		maxPoints = 10
+	}
+	return &FileStore{
+		path:      path,
+		maxPoints: maxPoints,
+	}
+}
+
+// Save the point; the file is replaced atomically so an interrupted write leaves the
+// previously saved points intact
+func (f *FileStore) Save(_ context.Context, point chainsync.Point) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	pp, err := f.load()
+	if err != nil {
+		return fmt.Errorf("failed to save point: %w", err)
+	}
+
+	pp = append(chainsync.Points{point}, pp...).Dedup()
+	sort.Sort(pp)
+	if len(pp) > f.maxPoints {
+		pp = pp[:f.maxPoints]
+	}
+
+	data, err := json.Marshal(pp)
+	if err != nil {
+		return fmt.Errorf("failed to save point: %w", err)
+	}
+	if err := f.write(data); err != nil {
+		return fmt.Errorf("failed to save point: %w", err)
+	}
+	return nil
+}
+
+// Load saved points, newest first
+func (f *FileStore) Load(context.Context) (chainsync.Points, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	pp, err := f.load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load points: %w", err)
+	}
+	return pp, nil
+}
+
+func (f *FileStore) load() (chainsync.Points, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pp chainsync.Points
+	if err := json.Unmarshal(data, &pp); err != nil {
+		return nil, fmt.Errorf("unable to decode %v: %w", f.path, err)
+	}
+	sort.Sort(pp)
+	return pp, nil
+}
+
+// write data to a temp file in the same directory and rename it over the original
+func (f *FileStore) write(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return renameFile(tmp.Name(), f.path)
+}
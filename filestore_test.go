@@ -0,0 +1,97 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+func TestFileStore(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		path  = filepath.Join(t.TempDir(), "points.json")
+		store = NewFileStore(path, 2)
+		a     = chainsync.PointStruct{Slot: 10, Hash: "a"}
+		b     = chainsync.PointStruct{Slot: 20, Hash: "b"}
+		c     = chainsync.PointStruct{Slot: 30, Hash: "c"}
+	)
+
+	pp, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if pp != nil {
+		t.Fatalf("got %v; want nil", pp)
+	}
+
+	for _, p := range []chainsync.PointStruct{a, c, b, c} {
+		if err := store.Save(ctx, p.Point()); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	}
+
+	pp, err = NewFileStore(path, 2).Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want := chainsync.Points{c.Point(), b.Point()}
+	if !reflect.DeepEqual(pp, want) {
+		t.Fatalf("got %v; want %v", pp, want)
+	}
+}
+
+func TestFileStore_crash(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		dir   = t.TempDir()
+		path  = filepath.Join(dir, "points.json")
+		store = NewFileStore(path, 10)
+		a     = chainsync.PointStruct{Slot: 10, Hash: "a"}
+		b     = chainsync.PointStruct{Slot: 20, Hash: "b"}
+	)
+
+	if err := store.Save(ctx, a.Point()); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	// a partially written temp file left behind by a crashed process
+	if err := os.WriteFile(path+".123.tmp", []byte(`[{"slot":`), 0644); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	// the process dies before the temp file replaces the original
+	crash := errors.New("crash")
+	renameFile = func(string, string) error { return crash }
+	defer func() { renameFile = os.Rename }()
+
+	if err := store.Save(ctx, b.Point()); !errors.Is(err, crash) {
+		t.Fatalf("got %v; want %v", err, crash)
+	}
+
+	pp, err := NewFileStore(path, 10).Load(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if want := (chainsync.Points{a.Point()}); !reflect.DeepEqual(pp, want) {
+		t.Fatalf("got %v; want %v", pp, want)
+	}
+}
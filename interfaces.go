@@ -0,0 +1,53 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/statequery"
+)
+
+// StateQuerier queries the ledger state; implemented by *Client
+type StateQuerier interface {
+	ChainTip(ctx context.Context) (chainsync.Point, error)
+	CurrentEpoch(ctx context.Context) (uint64, error)
+	CurrentProtocolParameters(ctx context.Context) (json.RawMessage, error)
+	EraStart(ctx context.Context) (statequery.EraStart, error)
+	EraSummaries(ctx context.Context) (*EraHistory, error)
+	UtxosByAddress(ctx context.Context, addresses ...string) ([]statequery.Utxo, error)
+	UtxosByTxIn(ctx context.Context, txIns ...chainsync.TxIn) ([]statequery.Utxo, error)
+}
+
+// TxSubmitter submits and evaluates transactions; implemented by *Client
+type TxSubmitter interface {
+	SubmitTx(ctx context.Context, data []byte) error
+	SubmitTxBytes(ctx context.Context, data []byte) error
+	EvaluateTx(ctx context.Context, cborHex string) (map[string]ExUnits, error)
+	EvaluateTxBytes(ctx context.Context, data []byte) (map[string]ExUnits, error)
+}
+
+// ChainSyncer replays the blockchain; implemented by *Client
+type ChainSyncer interface {
+	ChainSync(ctx context.Context, callback ChainSyncFunc, opts ...ChainSyncOption) (*ChainSync, error)
+}
+
+var (
+	_ StateQuerier = (*Client)(nil)
+	_ TxSubmitter  = (*Client)(nil)
+	_ ChainSyncer  = (*Client)(nil)
+)
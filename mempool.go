@@ -0,0 +1,183 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+// MempoolMonitor streams the transactions held in the mempool of the node using the
+// local-tx-monitor protocol.  Acquire a snapshot of the mempool with AcquireMempool and then
+// call NextTransaction until it returns nil; acquire again to observe newer transactions.
+// A MempoolMonitor holds a dedicated connection and must be closed when no longer needed.
+type MempoolMonitor struct {
+	client *Client
+	mutex  sync.Mutex
+	conn   *websocket.Conn
+}
+
+// MempoolMonitor opens a connection to ogmios for monitoring the mempool
+func (c *Client) MempoolMonitor(ctx context.Context) (*MempoolMonitor, error) {
+	conn, _, err := c.options.websocketDialer().DialContext(ctx, c.options.endpoint, nil)
+	if err != nil {
+		return nil, &UnreachableError{Endpoint: c.options.endpoint, Err: err}
+	}
+	return &MempoolMonitor{client: c, conn: conn}, nil
+}
+
+// AcquireMempool waits for, and acquires, a snapshot of the mempool that differs from the one
+// previously acquired, returning the slot at which the snapshot was taken
+func (m *MempoolMonitor) AcquireMempool(ctx context.Context) (uint64, error) {
+	var content struct {
+		Result struct {
+			AwaitAcquired *struct {
+				Slot uint64 `json:"slot"`
+			}
+		}
+	}
+	if err := m.exchange(ctx, makePayload("AwaitAcquire", Map{}), &content); err != nil {
+		return 0, fmt.Errorf("failed to acquire mempool: %w", err)
+	}
+	if content.Result.AwaitAcquired == nil {
+		return 0, fmt.Errorf("failed to acquire mempool: unexpected response")
+	}
+	return content.Result.AwaitAcquired.Slot, nil
+}
+
+// NextTransaction returns the next transaction of the acquired mempool snapshot; nil is
+// returned once all transactions of the snapshot have been returned
+func (m *MempoolMonitor) NextTransaction(ctx context.Context) (*chainsync.Tx, error) {
+	var content struct{ Result json.RawMessage }
+	if err := m.exchange(ctx, makePayload("NextTx", Map{"fields": "all"}), &content); err != nil {
+		return nil, fmt.Errorf("failed to fetch next mempool transaction: %w", err)
+	}
+	if len(content.Result) == 0 || string(content.Result) == "null" {
+		return nil, nil
+	}
+
+	var tx chainsync.Tx
+	if err := json.Unmarshal(content.Result, &tx); err != nil {
+		return nil, fmt.Errorf("failed to decode mempool transaction: %w", err)
+	}
+	return &tx, nil
+}
+
+// MempoolSize describes the size of an acquired mempool snapshot
+type MempoolSize struct {
+	MaxCapacity  uint64 `json:"capacity"`    // maximum size of the mempool, in bytes
+	CurrentSize  uint64 `json:"currentSize"` // current size of the mempool, in bytes
+	Transactions uint64 `json:"numberOfTxs"` // number of transactions in the mempool
+}
+
+// HasTransaction reports whether the acquired mempool snapshot contains the transaction
+func (m *MempoolMonitor) HasTransaction(ctx context.Context, id string) (bool, error) {
+	var content struct{ Result bool }
+	if err := m.exchange(ctx, makePayload("HasTx", Map{"id": id}), &content); err != nil {
+		return false, fmt.Errorf("failed to check mempool for transaction, %v: %w", id, err)
+	}
+	return content.Result, nil
+}
+
+// SizeOfMempool returns the size and capacity of the acquired mempool snapshot
+func (m *MempoolMonitor) SizeOfMempool(ctx context.Context) (MempoolSize, error) {
+	var content struct{ Result MempoolSize }
+	if err := m.exchange(ctx, makePayload("SizeAndCapacity", Map{}), &content); err != nil {
+		return MempoolSize{}, fmt.Errorf("failed to fetch mempool size: %w", err)
+	}
+	return content.Result, nil
+}
+
+// HasTransaction reports whether the mempool of the node currently contains the transaction
+func (c *Client) HasTransaction(ctx context.Context, id string) (bool, error) {
+	var ok bool
+	err := c.withMempool(ctx, func(m *MempoolMonitor) (err error) {
+		ok, err = m.HasTransaction(ctx, id)
+		return err
+	})
+	return ok, err
+}
+
+// SizeOfMempool returns the current size and capacity of the mempool of the node; useful for
+// applying backpressure before submitting transactions
+func (c *Client) SizeOfMempool(ctx context.Context) (MempoolSize, error) {
+	var size MempoolSize
+	err := c.withMempool(ctx, func(m *MempoolMonitor) (err error) {
+		size, err = m.SizeOfMempool(ctx)
+		return err
+	})
+	return size, err
+}
+
+// withMempool invokes fn with a freshly acquired mempool snapshot
+func (c *Client) withMempool(ctx context.Context, fn func(m *MempoolMonitor) error) error {
+	if err := c.options.limiter.wait(ctx); err != nil {
+		return err
+	}
+
+	m, err := c.MempoolMonitor(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if _, err := m.AcquireMempool(ctx); err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+// Close releases the acquired mempool snapshot, if any, and closes the connection
+func (m *MempoolMonitor) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = m.exchange(ctx, makePayload("ReleaseMempool", Map{}), nil) // fails if nothing acquired
+	return m.conn.Close()
+}
+
+// exchange sends the payload to ogmios and decodes the response into v.  As the connection is
+// shared across calls, cancellation of ctx leaves the MempoolMonitor unusable.
+func (m *MempoolMonitor) exchange(ctx context.Context, payload Map, v interface{}) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = m.conn.SetReadDeadline(time.Now()) // unblocks an in-flight read or write
+			_ = m.conn.SetWriteDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	method := m.client.mirror(payload)
+	if err := m.client.exchange(m.conn, payload, v); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("%v: %w", method, ctxErr)
+		}
+		return fmt.Errorf("%v: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// mempoolServer acquires at slot 42 and returns txs, and then null, from NextTx; HasTx
+// reports only tx "a" as present and requests for methods in stall are never answered
+func mempoolServer(t *testing.T, txs []string, stall ...string) string {
+	upgrader := websocket.Upgrader{}
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		c, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		next := 0
+		for {
+			var request struct {
+				MethodName string
+				Args       struct{ ID string }
+			}
+			if err := c.ReadJSON(&request); err != nil {
+				return
+			}
+
+			var result string
+			switch request.MethodName {
+			case "AwaitAcquire":
+				result, next = `{"AwaitAcquired":{"slot":42}}`, 0
+			case "NextTx":
+				result = "null"
+				if next < len(txs) {
+					result = txs[next]
+					next++
+				}
+			case "HasTx":
+				result = "false"
+				if request.Args.ID == "a" {
+					result = "true"
+				}
+			case "SizeAndCapacity":
+				result = `{"capacity":180224,"currentSize":1024,"numberOfTxs":2}`
+			case "ReleaseMempool":
+				result = `"Released"`
+			default:
+				t.Errorf("got unexpected method, %v", request.MethodName)
+				return
+			}
+			for _, method := range stall {
+				if method == request.MethodName {
+					result = ""
+				}
+			}
+			if result == "" {
+				continue
+			}
+
+			reply := `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"` + request.MethodName + `","result":` + result + `}`
+			if err := c.WriteMessage(websocket.TextMessage, []byte(reply)); err != nil {
+				return
+			}
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestMempoolMonitor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint := mempoolServer(t, []string{
+		`{"id":"a","body":{"fee":170000}}`,
+		`{"id":"b","body":{"fee":180000}}`,
+	})
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+
+	monitor, err := client.MempoolMonitor(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer monitor.Close()
+
+	slot, err := monitor.AcquireMempool(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := slot, uint64(42); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	var ids []string
+	for {
+		tx, err := monitor.NextTransaction(ctx)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if tx == nil {
+			break
+		}
+		ids = append(ids, tx.ID)
+	}
+	if got, want := strings.Join(ids, ","), "a,b"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if err := monitor.Close(); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+}
+
+func TestMempoolMonitor_cancel(t *testing.T) {
+	endpoint := mempoolServer(t, nil, "AwaitAcquire")
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+
+	monitor, err := client.MempoolMonitor(context.Background())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	defer monitor.conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := monitor.AcquireMempool(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestClient_HasTransaction(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint := mempoolServer(t, nil)
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+
+	for id, want := range map[string]bool{"a": true, "b": false} {
+		got, err := client.HasTransaction(ctx, id)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	}
+}
+
+func TestClient_SizeOfMempool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint := mempoolServer(t, nil)
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+
+	got, err := client.SizeOfMempool(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want := MempoolSize{MaxCapacity: 180224, CurrentSize: 1024, Transactions: 2}
+	if got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
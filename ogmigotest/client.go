@@ -0,0 +1,299 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ogmigotest provides an in-memory fake of the ogmigo client for tests that
+// should not depend on a running ogmios
+package ogmigotest
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/SundaeSwap-finance/ogmigo"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/statequery"
+)
+
+// Client is an in-memory implementation of ogmigo.StateQuerier, ogmigo.TxSubmitter, and
+// ogmigo.ChainSyncer seeded with blocks, utxos, and protocol parameters
+type Client struct {
+	mutex              sync.Mutex
+	blocks             []chainsync.RollForwardBlock
+	epoch              uint64
+	eraHistory         *ogmigo.EraHistory
+	eraStart           statequery.EraStart
+	exUnits            map[string]ogmigo.ExUnits
+	protocolParameters json.RawMessage
+	submitted          [][]byte
+	utxos              []statequery.Utxo
+}
+
+var (
+	_ ogmigo.StateQuerier = (*Client)(nil)
+	_ ogmigo.TxSubmitter  = (*Client)(nil)
+	_ ogmigo.ChainSyncer  = (*Client)(nil)
+)
+
+// New returns an empty Client
+func New() *Client {
+	return &Client{
+		eraHistory: &ogmigo.EraHistory{},
+	}
+}
+
+// AddBlocks appends blocks to the chain replayed by ChainSync
+func (c *Client) AddBlocks(blocks ...chainsync.RollForwardBlock) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.blocks = append(c.blocks, blocks...)
+	return c
+}
+
+// AddUtxos adds utxos returned by UtxosByAddress and UtxosByTxIn
+func (c *Client) AddUtxos(utxos ...statequery.Utxo) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.utxos = append(c.utxos, utxos...)
+	return c
+}
+
+// SetCurrentEpoch sets the epoch returned by CurrentEpoch
+func (c *Client) SetCurrentEpoch(epoch uint64) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.epoch = epoch
+	return c
+}
+
+// SetEraHistory sets the era history returned by EraSummaries and the start of the final
+// era returned by EraStart
+func (c *Client) SetEraHistory(history ogmigo.EraHistory) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.eraHistory = &history
+	c.eraStart = statequery.EraStart{}
+	if n := len(history.Summaries); n > 0 {
+		start := history.Summaries[n-1].Start
+		c.eraStart.Slot = start.Slot
+		c.eraStart.Epoch = start.Epoch
+	}
+	return c
+}
+
+// SetExUnits sets the execution units returned by EvaluateTx and EvaluateTxBytes
+func (c *Client) SetExUnits(exUnits map[string]ogmigo.ExUnits) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.exUnits = exUnits
+	return c
+}
+
+// SetProtocolParameters sets the parameters returned by CurrentProtocolParameters
+func (c *Client) SetProtocolParameters(params json.RawMessage) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.protocolParameters = params
+	return c
+}
+
+// Submitted returns the transactions received by SubmitTx and SubmitTxBytes
+func (c *Client) Submitted() [][]byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([][]byte(nil), c.submitted...)
+}
+
+// ChainSync invokes the callback with a RollForward for each seeded block, in order, using
+// the final block as the tip.  Done is closed once every block has been replayed.  Options
+// are not supported; the chain is always replayed from the first block.
+func (c *Client) ChainSync(ctx context.Context, callback ogmigo.ChainSyncFunc, _ ...ogmigo.ChainSyncOption) (*ogmigo.ChainSync, error) {
+	c.mutex.Lock()
+	blocks := append([]chainsync.RollForwardBlock(nil), c.blocks...)
+	c.mutex.Unlock()
+
+	tip := tipOf(blocks)
+	return ogmigo.StartChainSync(ctx, func(ctx context.Context) error {
+		for _, block := range blocks {
+			response := chainsync.Response{
+				Type:        "jsonwsp/response",
+				Version:     "1.0",
+				ServiceName: "ogmios",
+				MethodName:  "RequestNext",
+				Result: &chainsync.Result{
+					RollForward: &chainsync.RollForward{Block: block, Tip: tip},
+				},
+			}
+			data, err := json.Marshal(response)
+			if err != nil {
+				return fmt.Errorf("failed to encode block: %w", err)
+			}
+			if err := callback(ctx, data); err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return nil
+			}
+		}
+		return nil
+	}), nil
+}
+
+// ChainTip returns the point of the final seeded block or origin if there are no blocks
+func (c *Client) ChainTip(_ context.Context) (chainsync.Point, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return tipOf(c.blocks), nil
+}
+
+func (c *Client) CurrentEpoch(_ context.Context) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.epoch, nil
+}
+
+func (c *Client) CurrentProtocolParameters(_ context.Context) (json.RawMessage, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.protocolParameters, nil
+}
+
+func (c *Client) EraStart(_ context.Context) (statequery.EraStart, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.eraStart, nil
+}
+
+func (c *Client) EraSummaries(_ context.Context) (*ogmigo.EraHistory, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	history := *c.eraHistory
+	history.Summaries = append([]ogmigo.EraSummary(nil), history.Summaries...)
+	return &history, nil
+}
+
+// UtxosByAddress returns the seeded utxos held by any of the addresses
+func (c *Client) UtxosByAddress(_ context.Context, addresses ...string) ([]statequery.Utxo, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var utxos []statequery.Utxo
+	for _, utxo := range c.utxos {
+		for _, address := range addresses {
+			if utxo.TxOut.Address == address {
+				utxos = append(utxos, utxo)
+				break
+			}
+		}
+	}
+	return utxos, nil
+}
+
+// UtxosByTxIn returns the seeded utxos matching any of the txIns
+func (c *Client) UtxosByTxIn(_ context.Context, txIns ...chainsync.TxIn) ([]statequery.Utxo, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var utxos []statequery.Utxo
+	for _, utxo := range c.utxos {
+		for _, txIn := range txIns {
+			if utxo.TxIn == txIn {
+				utxos = append(utxos, utxo)
+				break
+			}
+		}
+	}
+	return utxos, nil
+}
+
+// SubmitTx records the transaction, given either as a json envelope containing cborHex or
+// as bare hex as with ogmigo.Client, for retrieval via Submitted
+func (c *Client) SubmitTx(_ context.Context, data []byte) error {
+	signedTx := string(bytes.TrimSpace(data))
+	if strings.HasPrefix(signedTx, "{") {
+		var content struct{ CborHex string }
+		if err := json.Unmarshal(data, &content); err != nil {
+			return fmt.Errorf("failed to decode signed tx: %w", err)
+		}
+		if content.CborHex != "" {
+			signedTx = content.CborHex
+		}
+	}
+	tx, err := hex.DecodeString(signedTx)
+	if err != nil {
+		return fmt.Errorf("failed to decode tx: %w", err)
+	}
+	c.record(tx)
+	return nil
+}
+
+// SubmitTxBytes records the transaction for retrieval via Submitted
+func (c *Client) SubmitTxBytes(_ context.Context, data []byte) error {
+	c.record(append([]byte(nil), data...))
+	return nil
+}
+
+func (c *Client) record(tx []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.submitted = append(c.submitted, tx)
+}
+
+// EvaluateTx returns the execution units set via SetExUnits
+func (c *Client) EvaluateTx(_ context.Context, cborHex string) (map[string]ogmigo.ExUnits, error) {
+	if _, err := hex.DecodeString(cborHex); err != nil {
+		return nil, fmt.Errorf("failed to decode tx: %w", err)
+	}
+	return c.evaluate(), nil
+}
+
+// EvaluateTxBytes returns the execution units set via SetExUnits
+func (c *Client) EvaluateTxBytes(_ context.Context, _ []byte) (map[string]ogmigo.ExUnits, error) {
+	return c.evaluate(), nil
+}
+
+func (c *Client) evaluate() map[string]ogmigo.ExUnits {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	exUnits := map[string]ogmigo.ExUnits{}
+	for k, v := range c.exUnits {
+		exUnits[k] = v
+	}
+	return exUnits
+}
+
+func tipOf(blocks []chainsync.RollForwardBlock) chainsync.Point {
+	if len(blocks) == 0 {
+		return chainsync.Origin
+	}
+	return blocks[len(blocks)-1].PointStruct().Point()
+}
@@ -0,0 +1,158 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigotest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/SundaeSwap-finance/ogmigo"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/statequery"
+)
+
+func TestClient_ChainSync(t *testing.T) {
+	client := New().AddBlocks(
+		chainsync.RollForwardBlock{Alonzo: &chainsync.Block{Header: chainsync.BlockHeader{Slot: 10, BlockHeight: 1}, HeaderHash: "a"}},
+		chainsync.RollForwardBlock{Babbage: &chainsync.Block{Header: chainsync.BlockHeader{Slot: 20, BlockHeight: 2}, HeaderHash: "b"}},
+	)
+
+	var (
+		ctx   = context.Background()
+		slots []uint64
+		eras  []chainsync.Era
+	)
+	var callback ogmigo.ChainSyncFunc = func(ctx context.Context, data []byte) error {
+		var response chainsync.Response
+		if err := json.Unmarshal(data, &response); err != nil {
+			return err
+		}
+		block := response.Result.RollForward.Block
+		slots = append(slots, block.PointStruct().Slot)
+		eras = append(eras, block.Era())
+		return nil
+	}
+
+	closer, err := client.ChainSync(ctx, callback)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	<-closer.Done()
+	if err := closer.Close(); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	if got, want := slots, []uint64{10, 20}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := eras, []chainsync.Era{chainsync.Alonzo, chainsync.Babbage}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	tip, err := client.ChainTip(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if ps, _ := tip.PointStruct(); ps == nil || ps.Slot != 20 || ps.Hash != "b" {
+		t.Fatalf("got %v; want slot 20", tip)
+	}
+}
+
+func TestClient_StateQuery(t *testing.T) {
+	var (
+		ctx = context.Background()
+		a   = statequery.Utxo{TxIn: chainsync.TxIn{TxHash: "a", Index: 0}, TxOut: chainsync.TxOut{Address: "addr1"}}
+		b   = statequery.Utxo{TxIn: chainsync.TxIn{TxHash: "b", Index: 1}, TxOut: chainsync.TxOut{Address: "addr2"}}
+	)
+	client := New().
+		AddUtxos(a, b).
+		SetCurrentEpoch(42).
+		SetProtocolParameters(json.RawMessage(`{"minFeeA":44}`)).
+		SetEraHistory(ogmigo.EraHistory{Summaries: []ogmigo.EraSummary{{Start: ogmigo.EraBound{Slot: 100, Epoch: 4}}}})
+
+	utxos, err := client.UtxosByAddress(ctx, "addr2", "addr3")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(utxos), 1; got != want || utxos[0].TxIn != b.TxIn {
+		t.Fatalf("got %v; want %v", utxos, b)
+	}
+
+	utxos, err = client.UtxosByTxIn(ctx, a.TxIn)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(utxos), 1; got != want || utxos[0].TxIn != a.TxIn {
+		t.Fatalf("got %v; want %v", utxos, a)
+	}
+
+	epoch, err := client.CurrentEpoch(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := epoch, uint64(42); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	params, err := client.CurrentProtocolParameters(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := string(params), `{"minFeeA":44}`; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	start, err := client.EraStart(ctx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := start, (statequery.EraStart{Slot: 100, Epoch: 4}); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestClient_TxSubmission(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		exUnits = map[string]ogmigo.ExUnits{"spend:0": {Memory: 1, Steps: 2}}
+		client  = New().SetExUnits(exUnits)
+	)
+
+	if err := client.SubmitTx(ctx, []byte(`{"cborHex":"84a0"}`)); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := client.SubmitTxBytes(ctx, []byte{0x84, 0xa1}); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := client.SubmitTx(ctx, []byte("84a2\n")); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := client.SubmitTx(ctx, []byte(`{"cborHex":"zz"}`)); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+	if got, want := client.Submitted(), [][]byte{{0x84, 0xa0}, {0x84, 0xa1}, {0x84, 0xa2}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %x; want %x", got, want)
+	}
+
+	got, err := client.EvaluateTx(ctx, "84a0")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if !reflect.DeepEqual(got, exUnits) {
+		t.Fatalf("got %v; want %v", got, exUnits)
+	}
+}
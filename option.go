@@ -14,24 +14,83 @@
 
 package ogmigo
 
+import (
+	"encoding/json"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
 // Options available to ogmios client
 type Options struct {
+	compression  bool
+	dialer       *websocket.Dialer
 	endpoint     string
+	frameTap     func(direction string, frame []byte)
+	idGenerator  func() json.RawMessage
+	limiter      *rateLimiter
 	logger       Logger
+	network      chainsync.Network
 	pipeline     int
 	saveInterval uint64
+	validateTxID bool
 }
 
 // Option to cardano client
 type Option func(*Options)
 
-// WithEndpoint allows ogmios endpoint to set; defaults to ws://127.0.0.1:1337
+// WithCompression negotiates permessage-deflate compression with ogmios.  Compression
+// significantly reduces the bandwidth consumed by chainsync at the cost of additional cpu
+// on both client and server; it is generally only worthwhile when ogmios is remote.
+func WithCompression(enabled bool) Option {
+	return func(opts *Options) {
+		opts.compression = enabled
+	}
+}
+
+// WithDialer allows the websocket dialer to be customized e.g. to configure a proxy or
+// handshake timeout; defaults to websocket.DefaultDialer.  A nil dialer keeps the current one.
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(opts *Options) {
+		if dialer != nil {
+			opts.dialer = dialer
+		}
+	}
+}
+
+// WithEndpoint allows ogmios endpoint to set; defaults to ws://127.0.0.1:1337.  http and
+// https endpoints are normalized to ws and wss; New panics on malformed endpoints
 func WithEndpoint(endpoint string) Option {
 	return func(opts *Options) {
 		opts.endpoint = endpoint
 	}
 }
 
+// Directions of frames passed to the WithFrameTap callback
+const (
+	FrameInbound  = "inbound"  // FrameInbound frames are received from ogmios
+	FrameOutbound = "outbound" // FrameOutbound frames are sent to ogmios
+)
+
+// WithFrameTap passes every websocket frame sent to or received from ogmios to fn, prior to
+// processing, to assist in diagnosing protocol mismatches.  fn may be invoked concurrently
+// and must not retain or modify frame.
+func WithFrameTap(fn func(direction string, frame []byte)) Option {
+	return func(opts *Options) {
+		opts.frameTap = fn
+	}
+}
+
+// WithIDGenerator allows the id attached to each request, and mirrored back by ogmios, to be
+// customized e.g. to use uuids for log correlation; ids must be valid json.  Defaults to an
+// incrementing counter.
+func WithIDGenerator(fn func() json.RawMessage) Option {
+	return func(opts *Options) {
+		opts.idGenerator = fn
+	}
+}
+
 // WithInterval specifies how frequently to save checkpoints when reading
 func WithInterval(n int) Option {
 	return func(options *Options) {
@@ -46,6 +105,15 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// WithNetwork fails ChainSync, and utxo queries, upon receiving a transaction or utxo
+// belonging to a network other than network e.g. when pointed at a testnet ogmios in place
+// of mainnet.  Errors wrap chainsync.ErrNetworkMismatch.  Byron addresses are not checked.
+func WithNetwork(network chainsync.Network) Option {
+	return func(opts *Options) {
+		opts.network = network
+	}
+}
+
 // WithPipeline allows number of pipelined ogmios requests to be provided
 func WithPipeline(n int) Option {
 	return func(opts *Options) {
@@ -53,14 +121,58 @@ func WithPipeline(n int) Option {
 	}
 }
 
+// WithRateLimit throttles queries to perSecond on average, permitting bursts of up to burst
+// queries, to protect an ogmios shared by many clients.  Queries wait for capacity until their
+// context is done.  The limit is shared by clients derived via WithDefaults.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(opts *Options) {
+		if perSecond <= 0 {
+			opts.limiter = nil
+			return
+		}
+		opts.limiter = newRateLimiter(perSecond, burst)
+	}
+}
+
+// WithValidateTxID computes the id of submitted transactions locally and verifies it
+// matches the id returned by ogmios
+func WithValidateTxID(enabled bool) Option {
+	return func(opts *Options) {
+		opts.validateTxID = enabled
+	}
+}
+
+// tap passes the frame to the frame tap, if one is configured
+func (o Options) tap(direction string, frame []byte) {
+	if o.frameTap != nil {
+		o.frameTap(direction, frame)
+	}
+}
+
+// websocketDialer returns the dialer configured with the requested compression
+func (o Options) websocketDialer() *websocket.Dialer {
+	if !o.compression || o.dialer.EnableCompression {
+		return o.dialer
+	}
+	dialer := *o.dialer
+	dialer.EnableCompression = true
+	return &dialer
+}
+
 func buildOptions(opts ...Option) Options {
 	var options Options
 	for _, opt := range opts {
 		opt(&options)
 	}
+	if options.dialer == nil {
+		options.dialer = websocket.DefaultDialer
+	}
 	if options.endpoint == "" {
 		options.endpoint = "ws://127.0.0.1:1337"
 	}
+	if options.idGenerator == nil {
+		options.idGenerator = nextRequestID
+	}
 	if options.logger == nil {
 		options.logger = DefaultLogger
 	}
@@ -17,6 +17,9 @@ package ogmigo
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 func TestWithInterval(t *testing.T) {
@@ -41,3 +44,17 @@ func TestWithPipeline(t *testing.T) {
 		t.Fatalf("got %v; want %v", got, want)
 	}
 }
+
+func TestWithDialer(t *testing.T) {
+	if got, want := buildOptions().dialer, websocket.DefaultDialer; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	dialer := &websocket.Dialer{HandshakeTimeout: time.Second}
+	if got, want := buildOptions(WithDialer(dialer)).dialer, dialer; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := buildOptions(WithDialer(dialer), WithDialer(nil)).dialer, dialer; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	base58Charset = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// paymentKeyHash returns the hex encoded payment key hash of a shelley address whose
+// payment credential is a key; false is returned for script and byron addresses
+func paymentKeyHash(address string) (string, bool) {
+	_, data, err := decodeBech32(address)
+	if err != nil || len(data) < 29 {
+		return "", false
+	}
+
+	// header types 0 - 7 carry a payment credential; odd types are scripts
+	if kind := data[0] >> 4; kind > 7 || kind%2 == 1 {
+		return "", false
+	}
+	return hex.EncodeToString(data[1:29]), true
+}
+
+// decodeAddress returns the raw bytes of a bech32 shelley address or base58 byron address
+func decodeAddress(address string) ([]byte, error) {
+	decode := decodeBase58
+	if isBech32(address) {
+		decode = func(s string) ([]byte, error) {
+			_, data, err := decodeBech32(s)
+			return data, err
+		}
+	}
+
+	data, err := decode(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address, %v: %w", address, err)
+	}
+	return data, nil
+}
+
+// isBech32 returns true if s has the form of a bech32 string, a human readable part and
+// separator followed by data, all in a single case; base58 byron addresses mix case
+func isBech32(s string) bool {
+	return strings.LastIndexByte(s, '1') > 0 && (s == strings.ToLower(s) || s == strings.ToUpper(s))
+}
+
+// decodeBase58 decodes a string using the bitcoin alphabet, as used by byron addresses
+func decodeBase58(s string) ([]byte, error) {
+	n := new(big.Int)
+	for _, c := range s {
+		v := strings.IndexRune(base58Charset, c)
+		if v < 0 {
+			return nil, fmt.Errorf("invalid base58 character, %q", c)
+		}
+		n.Mul(n, big.NewInt(58))
+		n.Add(n, big.NewInt(int64(v)))
+	}
+
+	// leading zeros are encoded as leading 1s
+	var zeros int
+	for zeros < len(s) && s[zeros] == base58Charset[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// decodeBech32 decodes and verifies the checksum of a bech32 string
+func decodeBech32(s string) (hrp string, data []byte, err error) {
+	s = strings.ToLower(s)
+	index := strings.LastIndexByte(s, '1')
+	if index < 1 || index+7 > len(s) {
+		return "", nil, fmt.Errorf("invalid bech32 string, %v", s)
+	}
+
+	hrp = s[:index]
+	values := make([]byte, 0, len(s)-index-1)
+	for _, c := range s[index+1:] {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 character, %q", c)
+		}
+		values = append(values, byte(v))
+	}
+
+	if bech32Polymod(append(bech32ExpandHRP(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("invalid bech32 checksum, %v", s)
+	}
+
+	// regroup the 5-bit values, less the checksum, into bytes
+	var (
+		acc  uint32
+		bits uint
+	)
+	for _, v := range values[:len(values)-6] {
+		acc = acc<<5 | uint32(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			data = append(data, byte(acc>>bits))
+		}
+	}
+	return hrp, data, nil
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"strings"
+	"testing"
+)
+
+const testAddress = "addr_test1qz6m03tdfm5raxr00fsw7p8v79ptfveaptar9a56zqz09kqkazwhq98h9v8gnk3wm5uvevzvd642zm7778afv0evwqgqfuy84f"
+
+func Test_paymentKeyHash(t *testing.T) {
+	got, ok := paymentKeyHash(testAddress)
+	if !ok {
+		t.Fatalf("got false; want true")
+	}
+	if want := "b5b7c56d4ee83e986f7a60ef04ecf142b4b33d0afa32f69a1004f2d8"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	// corrupt the checksum
+	if _, ok := paymentKeyHash(testAddress[:len(testAddress)-1] + "q"); ok {
+		t.Fatalf("got true; want false")
+	}
+	// byron addresses are not bech32
+	if _, ok := paymentKeyHash("Ae2tdPwUPEZFRbyhz3cpfC2CumGzNkFBN2L42rcUc2yjQpEkxDbkPodpMAi"); ok {
+		t.Fatalf("got true; want false")
+	}
+}
+
+func Test_decodeAddress(t *testing.T) {
+	t.Run("shelley", func(t *testing.T) {
+		data, err := decodeAddress(testAddress)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, want := len(data), 57; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("byron", func(t *testing.T) {
+		data, err := decodeAddress("Ae2tdPwUPEZFRbyhz3cpfC2CumGzNkFBN2L42rcUc2yjQpEkxDbkPodpMAi")
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		// byron addresses are a cbor array of the tagged address payload and its crc
+		if got, want := data[0], byte(0x82); got != want {
+			t.Fatalf("got %x; want %x", got, want)
+		}
+	})
+
+	t.Run("byron containing separator", func(t *testing.T) {
+		if _, err := decodeAddress("Ae2tdPwUPEZ1Rbyhz3cpfC2CumGzNkFBN2L42rcUc2yjQpEkxDbkPodpMAi"); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+
+	t.Run("bech32 checksum", func(t *testing.T) {
+		_, err := decodeAddress("stake_test1uqkazwhq98h9v8gnk3wm5uvevzvd642zm7778afv0evwqgqfw6rgg")
+		if err == nil {
+			t.Fatalf("got nil; want err")
+		}
+		if got, want := err.Error(), "invalid bech32 checksum"; !strings.Contains(got, want) {
+			t.Fatalf("got %v; want contains %v", got, want)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := decodeAddress("0OIl"); err == nil {
+			t.Fatalf("got nil; want err")
+		}
+	})
+}
@@ -0,0 +1,409 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// ReconstructBodyCBOR assembles the canonical CBOR encoding of the transaction body from
+// its typed fields.  Certificates and update proposals are not modelled and result in an
+// error.  As the original encoding need not have been canonical, the result may differ
+// from, and hash differently to, the body submitted on chain; prefer Raw when present.
+func (t Tx) ReconstructBodyCBOR() ([]byte, error) {
+	body, err := t.Body.cborValue(t.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct tx body, %v: %w", t.ID, err)
+	}
+
+	em, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cbor encoder: %w", err)
+	}
+
+	data, err := em.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tx body, %v: %w", t.ID, err)
+	}
+	return data, nil
+}
+
+// cborValue returns the tx body as a cbor map keyed by the body field indices of the ledger cddl
+func (b TxBody) cborValue(metadata json.RawMessage) (map[uint64]interface{}, error) {
+	if len(b.Certificates) > 0 {
+		return nil, fmt.Errorf("certificates are not supported")
+	}
+	if len(b.Update) > 0 && string(b.Update) != "null" {
+		return nil, fmt.Errorf("update proposals are not supported")
+	}
+
+	inputs, err := cborTxIns(b.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([]interface{}, 0, len(b.Outputs))
+	for _, txOut := range b.Outputs {
+		v, err := txOut.cborValue()
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, v)
+	}
+
+	body := map[uint64]interface{}{
+		0: inputs,
+		1: outputs,
+		2: b.Fee.BigInt(),
+	}
+
+	switch {
+	case b.TimeToLive > 0:
+		body[3] = b.TimeToLive
+	case b.ValidityInterval.InvalidHereafter > 0:
+		body[3] = b.ValidityInterval.InvalidHereafter
+	}
+
+	if len(b.Withdrawals) > 0 {
+		withdrawals := map[string]interface{}{}
+		for account, amount := range b.Withdrawals {
+			data, err := decodeAddress(account)
+			if err != nil {
+				return nil, err
+			}
+			withdrawals[string(data)] = amount
+		}
+		body[5] = cborBytesMap(withdrawals)
+	}
+
+	if len(metadata) > 0 {
+		var aux struct{ Hash string }
+		if err := json.Unmarshal(metadata, &aux); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata: %w", err)
+		}
+		if aux.Hash != "" {
+			hash, err := hex.DecodeString(aux.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("invalid metadata hash, %v: %w", aux.Hash, err)
+			}
+			body[7] = hash
+		}
+	}
+
+	if b.ValidityInterval.InvalidBefore > 0 {
+		body[8] = b.ValidityInterval.InvalidBefore
+	}
+
+	if b.Mint != nil && len(b.Mint.Assets) > 0 {
+		mint, err := cborMultiAsset(b.Mint.Assets)
+		if err != nil {
+			return nil, err
+		}
+		body[9] = mint
+	}
+
+	if b.ScriptIntegrityHash != "" {
+		hash, err := hex.DecodeString(b.ScriptIntegrityHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script integrity hash, %v: %w", b.ScriptIntegrityHash, err)
+		}
+		body[11] = hash
+	}
+
+	if len(b.Collaterals) > 0 {
+		collaterals, err := cborTxIns(b.Collaterals)
+		if err != nil {
+			return nil, err
+		}
+		body[13] = collaterals
+	}
+
+	if len(b.RequiredExtraSignatures) > 0 {
+		signers := make([][]byte, 0, len(b.RequiredExtraSignatures))
+		for _, s := range b.RequiredExtraSignatures {
+			signer, err := hex.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid required signer, %v: %w", s, err)
+			}
+			signers = append(signers, signer)
+		}
+		body[14] = signers
+	}
+
+	if len(b.Network) > 0 && string(b.Network) != "null" {
+		var network string
+		if err := json.Unmarshal(b.Network, &network); err != nil {
+			return nil, fmt.Errorf("failed to decode network: %w", err)
+		}
+		switch network {
+		case "mainnet":
+			body[15] = 1
+		case "testnet":
+			body[15] = 0
+		default:
+			return nil, fmt.Errorf("unknown network, %v", network)
+		}
+	}
+
+	if b.CollateralReturn != nil {
+		v, err := b.CollateralReturn.cborValue()
+		if err != nil {
+			return nil, err
+		}
+		body[16] = v
+	}
+
+	if b.TotalCollateral != nil {
+		body[17] = *b.TotalCollateral
+	}
+
+	if len(b.References) > 0 {
+		references, err := cborTxIns(b.References)
+		if err != nil {
+			return nil, err
+		}
+		body[18] = references
+	}
+
+	return body, nil
+}
+
+// cborValue returns the tx output in the legacy array format unless it carries an inline
+// datum or script reference, which require the babbage map format
+func (t TxOut) cborValue() (interface{}, error) {
+	address, err := decodeAddress(t.Address)
+	if err != nil {
+		return nil, err
+	}
+	value, err := t.Value.cborValue()
+	if err != nil {
+		return nil, err
+	}
+
+	var datumHash []byte
+	if t.DatumHash != "" {
+		if datumHash, err = hex.DecodeString(t.DatumHash); err != nil {
+			return nil, fmt.Errorf("invalid datum hash, %v: %w", t.DatumHash, err)
+		}
+	}
+
+	if t.Datum == "" && len(t.Script) == 0 {
+		if datumHash != nil {
+			return []interface{}{address, value, datumHash}, nil
+		}
+		return []interface{}{address, value}, nil
+	}
+
+	output := map[uint64]interface{}{
+		0: address,
+		1: value,
+	}
+	switch {
+	case t.Datum != "":
+		datum, err := hex.DecodeString(t.Datum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid datum, %v: %w", t.Datum, err)
+		}
+		output[2] = []interface{}{1, cbor.Tag{Number: 24, Content: datum}}
+	case datumHash != nil:
+		output[2] = []interface{}{0, datumHash}
+	}
+	if len(t.Script) > 0 {
+		script, err := cborScriptRef(t.Script)
+		if err != nil {
+			return nil, err
+		}
+		output[3] = script
+	}
+	return output, nil
+}
+
+// cborValue returns the coin alone when the value holds no assets
+func (v Value) cborValue() (interface{}, error) {
+	if len(v.Assets) == 0 {
+		return v.Coins.BigInt(), nil
+	}
+	assets, err := cborMultiAsset(v.Assets)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{v.Coins.BigInt(), assets}, nil
+}
+
+// cborValue returns the native script encoded per the ledger cddl
+func (s NativeScript) cborValue() (interface{}, error) {
+	switch s.Type {
+	case NativeScriptSignature:
+		keyHash, err := hex.DecodeString(s.KeyHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key hash, %v: %w", s.KeyHash, err)
+		}
+		return []interface{}{0, keyHash}, nil
+	case NativeScriptAll, NativeScriptAny, NativeScriptAtLeast:
+		scripts := make([]interface{}, 0, len(s.Scripts))
+		for _, script := range s.Scripts {
+			v, err := script.cborValue()
+			if err != nil {
+				return nil, err
+			}
+			scripts = append(scripts, v)
+		}
+		switch s.Type {
+		case NativeScriptAll:
+			return []interface{}{1, scripts}, nil
+		case NativeScriptAny:
+			return []interface{}{2, scripts}, nil
+		default:
+			return []interface{}{3, s.Required, scripts}, nil
+		}
+	case NativeScriptAfter:
+		return []interface{}{4, s.Slot}, nil
+	case NativeScriptBefore:
+		return []interface{}{5, s.Slot}, nil
+	default:
+		return nil, fmt.Errorf("unknown native script type, %v", s.Type)
+	}
+}
+
+// cborScriptRef encodes a script, as reported by ogmios, as an output script reference
+func cborScriptRef(data json.RawMessage) (interface{}, error) {
+	script, err := ParseScript(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var ref interface{}
+	if script.Language == ScriptNative {
+		v, err := script.Native.cborValue()
+		if err != nil {
+			return nil, err
+		}
+		ref = []interface{}{0, v}
+	} else {
+		tag, v, err := script.tagged()
+		if err != nil {
+			return nil, err
+		}
+		ref = []interface{}{tag, v}
+	}
+
+	encoded, err := cbor.Marshal(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode script: %w", err)
+	}
+	return cbor.Tag{Number: 24, Content: encoded}, nil
+}
+
+func cborTxIns(txIns []TxIn) ([]interface{}, error) {
+	result := make([]interface{}, 0, len(txIns))
+	for _, txIn := range txIns {
+		txHash, err := hex.DecodeString(txIn.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tx hash, %v: %w", txIn.TxHash, err)
+		}
+		result = append(result, []interface{}{txHash, txIn.Index})
+	}
+	return result, nil
+}
+
+func cborMultiAsset(assets map[AssetID]num.Int) (interface{}, error) {
+	policies := map[string]map[string]interface{}{}
+	for assetID, amount := range assets {
+		policyID, err := hex.DecodeString(assetID.PolicyID())
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy id, %v: %w", assetID, err)
+		}
+		assetName, err := hex.DecodeString(assetID.AssetName())
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset name, %v: %w", assetID, err)
+		}
+		if policies[string(policyID)] == nil {
+			policies[string(policyID)] = map[string]interface{}{}
+		}
+		policies[string(policyID)][string(assetName)] = amount.BigInt()
+	}
+
+	result := map[string]interface{}{}
+	for policyID, names := range policies {
+		result[policyID] = cborBytesMap(names)
+	}
+	return cborBytesMap(result), nil
+}
+
+// cborBytesMap encodes as a canonical cbor map keyed by byte strings; cbor would otherwise
+// encode string keys as text and []byte may not be used as a map key
+type cborBytesMap map[string]interface{}
+
+func (m cborBytesMap) MarshalCBOR() ([]byte, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	// canonical ordering sorts shorter keys first, then lexically
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	em, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		return nil, err
+	}
+
+	data := cborHeader(5, uint64(len(m)))
+	for _, k := range keys {
+		key, err := em.Marshal([]byte(k))
+		if err != nil {
+			return nil, err
+		}
+		value, err := em.Marshal(m[k])
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, key...)
+		data = append(data, value...)
+	}
+	return data, nil
+}
+
+// cborHeader encodes the initial bytes of a cbor data item of the given major type and argument
+func cborHeader(major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return []byte{major | byte(n)}
+	case n <= math.MaxUint8:
+		return []byte{major | 24, byte(n)}
+	case n <= math.MaxUint16:
+		return []byte{major | 25, byte(n >> 8), byte(n)}
+	case n <= math.MaxUint32:
+		return []byte{major | 26, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	default:
+		data := []byte{major | 27}
+		for shift := 56; shift >= 0; shift -= 8 {
+			data = append(data, byte(n>>shift))
+		}
+		return data
+	}
+}
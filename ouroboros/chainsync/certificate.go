@@ -0,0 +1,180 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Certificate types as reported by ogmios; each certificate is an object with a single key
+// naming its type e.g. {"stakeDelegation":{"delegator":"...","delegatee":"..."}}
+const (
+	CertificateStakeKeyRegistration   = "stakeKeyRegistration"
+	CertificateStakeKeyDeregistration = "stakeKeyDeregistration"
+	CertificateStakeDelegation        = "stakeDelegation"
+	CertificatePoolRegistration       = "poolRegistration"
+	CertificatePoolRetirement         = "poolRetirement"
+	CertificateGenesisDelegation      = "genesisDelegation"
+	CertificateMoveInstantaneous      = "moveInstantaneousRewards"
+)
+
+// Certificate is a certificate carried by a transaction.  The payload of modelled types is held
+// in the field matching Type; Raw holds the json reported by ogmios so that certificates of
+// other types e.g. poolRegistration, remain available and round trip unchanged.
+type Certificate struct {
+	Type                  string
+	StakeCredential       string                 // StakeCredential for stakeKeyRegistration and stakeKeyDeregistration
+	StakeDelegation       *StakeDelegation       // StakeDelegation for stakeDelegation
+	StakePoolRetirement   *StakePoolRetirement   // StakePoolRetirement for poolRetirement
+	GenesisDelegationCert *GenesisDelegationCert // GenesisDelegationCert for genesisDelegation
+	Raw                   json.RawMessage
+}
+
+// StakeDelegation delegates the stake of Delegator to the pool, Delegatee
+type StakeDelegation struct {
+	Delegator string `json:"delegator"`
+	Delegatee string `json:"delegatee"`
+}
+
+// StakePoolRetirement schedules the retirement of a stake pool at the start of RetirementEpoch
+type StakePoolRetirement struct {
+	PoolID          string `json:"poolId"`
+	RetirementEpoch uint64 `json:"retirementEpoch"`
+}
+
+// GenesisDelegationCert delegates the block production rights of the genesis key identified by
+// VerificationKeyHash to the delegate key, DelegateKeyHash, and its vrf key
+type GenesisDelegationCert struct {
+	DelegateKeyHash        string `json:"delegateKeyHash"`
+	VerificationKeyHash    string `json:"verificationKeyHash"`
+	VrfVerificationKeyHash string `json:"vrfVerificationKeyHash"`
+}
+
+// GenesisDelegation returns the payload of a genesisDelegation certificate
+func (c Certificate) GenesisDelegation() (*GenesisDelegationCert, bool) {
+	return c.GenesisDelegationCert, c.GenesisDelegationCert != nil
+}
+
+func (c Certificate) MarshalJSON() ([]byte, error) {
+	if len(c.Raw) > 0 {
+		return c.Raw, nil
+	}
+
+	var payload interface{}
+	switch c.Type {
+	case CertificateStakeKeyRegistration, CertificateStakeKeyDeregistration:
+		payload = c.StakeCredential
+	case CertificateStakeDelegation:
+		payload = c.StakeDelegation
+	case CertificatePoolRetirement:
+		payload = c.StakePoolRetirement
+	case CertificateGenesisDelegation:
+		payload = c.GenesisDelegationCert
+	default:
+		return nil, fmt.Errorf("failed to encode certificate: no raw json for type, %v", c.Type)
+	}
+	return json.Marshal(map[string]interface{}{c.Type: payload})
+}
+
+func (c *Certificate) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to decode certificate: %w", err)
+	}
+	if len(fields) != 1 {
+		return fmt.Errorf("failed to decode certificate: expected a single key, %v", string(data))
+	}
+
+	*c = Certificate{Raw: append(json.RawMessage(nil), data...)}
+	for key, payload := range fields {
+		c.Type = key
+
+		var err error
+		switch key {
+		case CertificateStakeKeyRegistration, CertificateStakeKeyDeregistration:
+			err = json.Unmarshal(payload, &c.StakeCredential)
+		case CertificateStakeDelegation:
+			c.StakeDelegation = &StakeDelegation{}
+			err = json.Unmarshal(payload, c.StakeDelegation)
+		case CertificatePoolRetirement:
+			c.StakePoolRetirement = &StakePoolRetirement{}
+			err = json.Unmarshal(payload, c.StakePoolRetirement)
+		case CertificateGenesisDelegation:
+			c.GenesisDelegationCert = &GenesisDelegationCert{}
+			err = json.Unmarshal(payload, c.GenesisDelegationCert)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode %v certificate: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// MarshalDynamoDBAttributeValue stores the certificate as json bytes, as certificates were
+// stored prior to being typed
+func (c Certificate) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	data, err := c.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	item.B = data
+	return nil
+}
+
+func (c *Certificate) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	if item == nil || len(item.B) == 0 {
+		return nil
+	}
+	return c.UnmarshalJSON(item.B)
+}
+
+// ContainsCertificateType returns true if the transaction carries a certificate of the
+// given type e.g. CertificateStakeDelegation
+func (t Tx) ContainsCertificateType(certType string) bool {
+	for _, cert := range t.Body.Certificates {
+		if cert.Type == certType {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistersStakeKey returns true if the transaction registers a stake key
+func (t Tx) RegistersStakeKey() bool {
+	return t.ContainsCertificateType(CertificateStakeKeyRegistration)
+}
+
+// DeregistersStakeKey returns true if the transaction deregisters a stake key
+func (t Tx) DeregistersStakeKey() bool {
+	return t.ContainsCertificateType(CertificateStakeKeyDeregistration)
+}
+
+// DelegatesStake returns true if the transaction delegates stake to a pool
+func (t Tx) DelegatesStake() bool {
+	return t.ContainsCertificateType(CertificateStakeDelegation)
+}
+
+// RegistersPool returns true if the transaction registers, or updates, a stake pool
+func (t Tx) RegistersPool() bool {
+	return t.ContainsCertificateType(CertificatePoolRegistration)
+}
+
+// RetiresPool returns true if the transaction schedules the retirement of a stake pool
+func (t Tx) RetiresPool() bool {
+	return t.ContainsCertificateType(CertificatePoolRetirement)
+}
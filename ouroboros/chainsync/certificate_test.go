@@ -0,0 +1,195 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+func TestTx_ContainsCertificateType(t *testing.T) {
+	tests := map[string]struct {
+		Certificates string
+		Want         map[string]bool
+	}{
+		"none": {
+			Certificates: `[]`,
+			Want:         map[string]bool{},
+		},
+		"delegation": {
+			Certificates: `[{"stakeKeyRegistration":"abc"},{"stakeDelegation":{"delegator":"abc","delegatee":"pool1"}}]`,
+			Want: map[string]bool{
+				CertificateStakeKeyRegistration: true,
+				CertificateStakeDelegation:      true,
+			},
+		},
+		"pool retirement": {
+			Certificates: `[{"poolRetirement":{"poolId":"pool1","retirementEpoch":300}}]`,
+			Want: map[string]bool{
+				CertificatePoolRetirement: true,
+			},
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var tx Tx
+			if err := json.Unmarshal([]byte(tc.Certificates), &tx.Body.Certificates); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+
+			for _, certType := range []string{
+				CertificateStakeKeyRegistration,
+				CertificateStakeKeyDeregistration,
+				CertificateStakeDelegation,
+				CertificatePoolRegistration,
+				CertificatePoolRetirement,
+			} {
+				if got, want := tx.ContainsCertificateType(certType), tc.Want[certType]; got != want {
+					t.Fatalf("%v: got %v; want %v", certType, got, want)
+				}
+			}
+			if got, want := tx.DelegatesStake(), tc.Want[CertificateStakeDelegation]; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			if got, want := tx.RetiresPool(), tc.Want[CertificatePoolRetirement]; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCertificate(t *testing.T) {
+	tests := map[string]struct {
+		Data  string
+		Check func(t *testing.T, c Certificate)
+	}{
+		"stake key registration": {
+			Data: `{"stakeKeyRegistration":"abc"}`,
+			Check: func(t *testing.T, c Certificate) {
+				if got, want := c.StakeCredential, "abc"; got != want {
+					t.Fatalf("got %v; want %v", got, want)
+				}
+			},
+		},
+		"stake delegation": {
+			Data: `{"stakeDelegation":{"delegator":"abc","delegatee":"pool1"}}`,
+			Check: func(t *testing.T, c Certificate) {
+				if got, want := c.StakeDelegation, (&StakeDelegation{Delegator: "abc", Delegatee: "pool1"}); !reflect.DeepEqual(got, want) {
+					t.Fatalf("got %v; want %v", got, want)
+				}
+			},
+		},
+		"pool retirement": {
+			Data: `{"poolRetirement":{"poolId":"pool1","retirementEpoch":300}}`,
+			Check: func(t *testing.T, c Certificate) {
+				if got, want := c.StakePoolRetirement, (&StakePoolRetirement{PoolID: "pool1", RetirementEpoch: 300}); !reflect.DeepEqual(got, want) {
+					t.Fatalf("got %v; want %v", got, want)
+				}
+			},
+		},
+		"genesis delegation": {
+			Data: `{"genesisDelegation":{"delegateKeyHash":"a","verificationKeyHash":"b","vrfVerificationKeyHash":"c"}}`,
+			Check: func(t *testing.T, c Certificate) {
+				want := &GenesisDelegationCert{DelegateKeyHash: "a", VerificationKeyHash: "b", VrfVerificationKeyHash: "c"}
+				if got := c.GenesisDelegationCert; !reflect.DeepEqual(got, want) {
+					t.Fatalf("got %v; want %v", got, want)
+				}
+				if got, ok := c.GenesisDelegation(); !ok || !reflect.DeepEqual(got, want) {
+					t.Fatalf("got %v, %v; want %v, true", got, ok, want)
+				}
+			},
+		},
+		"unmodelled": {
+			Data: `{"moveInstantaneousRewards":{"pot":"reserves","rewards":{"abc":1}}}`,
+			Check: func(t *testing.T, c Certificate) {
+				if c.StakeDelegation != nil || c.StakePoolRetirement != nil || c.GenesisDelegationCert != nil {
+					t.Fatalf("got %#v; want no typed payload", c)
+				}
+				if _, ok := c.GenesisDelegation(); ok {
+					t.Fatalf("got true; want false")
+				}
+			},
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var cert Certificate
+			if err := json.Unmarshal([]byte(tc.Data), &cert); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			var key map[string]json.RawMessage
+			_ = json.Unmarshal([]byte(tc.Data), &key)
+			for want := range key {
+				if got := cert.Type; got != want {
+					t.Fatalf("got %v; want %v", got, want)
+				}
+			}
+			tc.Check(t, cert)
+
+			data, err := json.Marshal(cert)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := string(data), tc.Data; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+
+			item, err := dynamodbattribute.Marshal(cert)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			var decoded Certificate
+			if err := dynamodbattribute.Unmarshal(item, &decoded); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if !reflect.DeepEqual(decoded, cert) {
+				t.Fatalf("got %#v; want %#v", decoded, cert)
+			}
+		})
+	}
+
+	t.Run("without raw", func(t *testing.T) {
+		cert := Certificate{
+			Type:            CertificateStakeDelegation,
+			StakeDelegation: &StakeDelegation{Delegator: "abc", Delegatee: "pool1"},
+		}
+		data, err := json.Marshal(cert)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, want := string(data), `{"stakeDelegation":{"delegator":"abc","delegatee":"pool1"}}`; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("stored as json bytes", func(t *testing.T) {
+		var body TxBody
+		if err := json.Unmarshal([]byte(`{"certificates":[{"stakeKeyDeregistration":"abc"}]}`), &body); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		item, err := dynamodbattribute.Marshal(body)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, want := string(item.M["certificates"].L[0].B), `{"stakeKeyDeregistration":"abc"}`; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+}
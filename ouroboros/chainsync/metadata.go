@@ -0,0 +1,234 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// MetadatumType identifies the kind of value held by an OgmiosMetadatum
+type MetadatumType int
+
+const (
+	MetadatumInt    MetadatumType = 1
+	MetadatumString MetadatumType = 2
+	MetadatumBytes  MetadatumType = 3
+	MetadatumList   MetadatumType = 4
+	MetadatumMap    MetadatumType = 5
+)
+
+// OgmiosMetadatum is a transaction metadata value as reported by ogmios e.g. {"int":42},
+// {"string":"msg"}, {"bytes":"<hex>"}, {"list":[...]}, or {"map":[{"k":...,"v":...}]}
+type OgmiosMetadatum struct {
+	Type   MetadatumType
+	Int    *big.Int
+	String string
+	Bytes  []byte
+	List   []OgmiosMetadatum
+	Map    []OgmiosMetadatumEntry
+}
+
+// OgmiosMetadatumEntry is a key value pair of a MetadatumMap
+type OgmiosMetadatumEntry struct {
+	Key   OgmiosMetadatum `json:"k"`
+	Value OgmiosMetadatum `json:"v"`
+}
+
+// Equal returns true if m and other hold the same value.  Maps are compared without regard
+// to the order of their entries.
+func (m OgmiosMetadatum) Equal(other OgmiosMetadatum) bool {
+	if m.Type != other.Type {
+		return false
+	}
+
+	switch m.Type {
+	case MetadatumInt:
+		if m.Int == nil || other.Int == nil {
+			return m.Int == other.Int
+		}
+		return m.Int.Cmp(other.Int) == 0
+	case MetadatumString:
+		return m.String == other.String
+	case MetadatumBytes:
+		return bytes.Equal(m.Bytes, other.Bytes)
+	case MetadatumList:
+		if len(m.List) != len(other.List) {
+			return false
+		}
+		for i := range m.List {
+			if !m.List[i].Equal(other.List[i]) {
+				return false
+			}
+		}
+		return true
+	case MetadatumMap:
+		if len(m.Map) != len(other.Map) {
+			return false
+		}
+		matched := make([]bool, len(other.Map))
+	entries:
+		for _, entry := range m.Map {
+			for i, candidate := range other.Map {
+				if !matched[i] && entry.Key.Equal(candidate.Key) && entry.Value.Equal(candidate.Value) {
+					matched[i] = true
+					continue entries
+				}
+			}
+			return false
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// Hash returns a blake2b-256 hash of the canonical form of the metadatum, suitable as a key
+// for deduplication; values that are Equal share the same hash
+func (m OgmiosMetadatum) Hash() [32]byte {
+	buf := bytes.NewBuffer(nil)
+	writeLen := func(n int) {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(n))
+		buf.Write(b[:])
+	}
+
+	buf.WriteByte(byte(m.Type))
+	switch m.Type {
+	case MetadatumInt:
+		if m.Int != nil {
+			text := m.Int.String()
+			writeLen(len(text))
+			buf.WriteString(text)
+		}
+	case MetadatumString:
+		writeLen(len(m.String))
+		buf.WriteString(m.String)
+	case MetadatumBytes:
+		writeLen(len(m.Bytes))
+		buf.Write(m.Bytes)
+	case MetadatumList:
+		writeLen(len(m.List))
+		for _, item := range m.List {
+			hash := item.Hash()
+			buf.Write(hash[:])
+		}
+	case MetadatumMap:
+		// sort entries by hash so the hash is independent of entry order
+		entries := make([][]byte, 0, len(m.Map))
+		for _, entry := range m.Map {
+			k, v := entry.Key.Hash(), entry.Value.Hash()
+			entries = append(entries, append(k[:], v[:]...))
+		}
+		sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i], entries[j]) < 0 })
+		writeLen(len(entries))
+		for _, entry := range entries {
+			buf.Write(entry)
+		}
+	}
+	return blake2b.Sum256(buf.Bytes())
+}
+
+type metadatumJSON struct {
+	Int    *big.Int                `json:"int,omitempty"`
+	String *string                 `json:"string,omitempty"`
+	Bytes  *string                 `json:"bytes,omitempty"`
+	List   *[]OgmiosMetadatum      `json:"list,omitempty"`
+	Map    *[]OgmiosMetadatumEntry `json:"map,omitempty"`
+}
+
+func (m OgmiosMetadatum) MarshalJSON() ([]byte, error) {
+	var v metadatumJSON
+	switch m.Type {
+	case MetadatumInt:
+		v.Int = m.Int
+		if v.Int == nil {
+			v.Int = new(big.Int)
+		}
+	case MetadatumString:
+		v.String = &m.String
+	case MetadatumBytes:
+		s := hex.EncodeToString(m.Bytes)
+		v.Bytes = &s
+	case MetadatumList:
+		list := m.List
+		if list == nil {
+			list = []OgmiosMetadatum{}
+		}
+		v.List = &list
+	case MetadatumMap:
+		entries := m.Map
+		if entries == nil {
+			entries = []OgmiosMetadatumEntry{}
+		}
+		v.Map = &entries
+	default:
+		return nil, fmt.Errorf("unknown metadatum type, %v", m.Type)
+	}
+	return json.Marshal(v)
+}
+
+func (m *OgmiosMetadatum) UnmarshalJSON(data []byte) error {
+	var v metadatumJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("failed to decode metadatum: %w", err)
+	}
+
+	*m = OgmiosMetadatum{}
+	switch {
+	case v.Int != nil:
+		m.Type, m.Int = MetadatumInt, v.Int
+	case v.String != nil:
+		m.Type, m.String = MetadatumString, *v.String
+	case v.Bytes != nil:
+		data, err := hex.DecodeString(*v.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to decode metadatum bytes: %w", err)
+		}
+		m.Type, m.Bytes = MetadatumBytes, data
+	case v.List != nil:
+		m.Type, m.List = MetadatumList, *v.List
+	case v.Map != nil:
+		m.Type, m.Map = MetadatumMap, *v.Map
+	default:
+		return fmt.Errorf("failed to decode metadatum: unknown metadatum, %v", string(data))
+	}
+	return nil
+}
+
+// MetadataBlob returns the metadata of the transaction keyed by label e.g. 674 for CIP-20
+// messages; nil is returned if the transaction carries no metadata
+func (t Tx) MetadataBlob() (map[string]OgmiosMetadatum, error) {
+	if len(t.Metadata) == 0 || string(t.Metadata) == "null" {
+		return nil, nil
+	}
+
+	var metadata struct {
+		Body struct {
+			Blob map[string]OgmiosMetadatum `json:"blob"`
+		} `json:"body"`
+	}
+	if err := json.Unmarshal(t.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+	return metadata.Body.Blob, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeMetadatum(t *testing.T, s string) OgmiosMetadatum {
+	var m OgmiosMetadatum
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return m
+}
+
+func TestOgmiosMetadatum_Equal(t *testing.T) {
+	tests := map[string]struct {
+		A, B string
+		Want bool
+	}{
+		"int": {
+			A: `{"int":340282366920938463463374607431768211456}`, B: `{"int":340282366920938463463374607431768211456}`, Want: true,
+		},
+		"int differs": {
+			A: `{"int":1}`, B: `{"int":2}`,
+		},
+		"type differs": {
+			A: `{"int":1}`, B: `{"string":"1"}`,
+		},
+		"bytes": {
+			A: `{"bytes":"cafe"}`, B: `{"bytes":"cafe"}`, Want: true,
+		},
+		"list order matters": {
+			A: `{"list":[{"int":1},{"int":2}]}`, B: `{"list":[{"int":2},{"int":1}]}`,
+		},
+		"map order ignored": {
+			A:    `{"map":[{"k":{"string":"a"},"v":{"int":1}},{"k":{"string":"b"},"v":{"list":[{"bytes":"00"}]}}]}`,
+			B:    `{"map":[{"k":{"string":"b"},"v":{"list":[{"bytes":"00"}]}},{"k":{"string":"a"},"v":{"int":1}}]}`,
+			Want: true,
+		},
+		"map value differs": {
+			A: `{"map":[{"k":{"string":"a"},"v":{"int":1}}]}`, B: `{"map":[{"k":{"string":"a"},"v":{"int":2}}]}`,
+		},
+		"cip-20": {
+			A:    `{"map":[{"k":{"string":"msg"},"v":{"list":[{"string":"hello"},{"string":"world"}]}}]}`,
+			B:    `{"map":[{"k":{"string":"msg"},"v":{"list":[{"string":"hello"},{"string":"world"}]}}]}`,
+			Want: true,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			a, b := decodeMetadatum(t, tc.A), decodeMetadatum(t, tc.B)
+			if got := a.Equal(b); got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+			if got := b.Equal(a); got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+			if got := a.Hash() == b.Hash(); got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+		})
+	}
+}
+
+func TestOgmiosMetadatum_JSON(t *testing.T) {
+	data := `{"map":[{"k":{"int":1},"v":{"list":[{"bytes":"cafe"},{"string":"s"},{"list":[]}]}}]}`
+
+	m := decodeMetadatum(t, data)
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := string(encoded), data; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	var invalid OgmiosMetadatum
+	if err := json.Unmarshal([]byte(`{"float":1.5}`), &invalid); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func TestTx_MetadataBlob(t *testing.T) {
+	tx := Tx{Metadata: json.RawMessage(`{"hash":"00","body":{"blob":{"674":{"map":[{"k":{"string":"msg"},"v":{"list":[{"string":"hello"}]}}]}}}}`)}
+
+	blob, err := tx.MetadataBlob()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want := decodeMetadatum(t, `{"map":[{"k":{"string":"msg"},"v":{"list":[{"string":"hello"}]}}]}`)
+	if got := blob["674"]; !got.Equal(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if blob, err := (Tx{}).MetadataBlob(); err != nil || blob != nil {
+		t.Fatalf("got %v, %v; want nil, nil", blob, err)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNetworkMismatch indicates a transaction or address belongs to an unexpected network
+var ErrNetworkMismatch = errors.New("network mismatch")
+
+// Network identifies the network of a transaction or address as reported by ogmios.  All
+// test networks e.g. preview and preprod, share NetworkTestnet.
+type Network string
+
+const (
+	NetworkMainnet Network = "mainnet"
+	NetworkTestnet Network = "testnet"
+)
+
+// AddressNetwork returns the network of a bech32 encoded shelley address; false is returned
+// for byron addresses which do not carry a bech32 prefix
+func AddressNetwork(address string) (Network, bool) {
+	switch {
+	case strings.HasPrefix(address, "addr_test1"), strings.HasPrefix(address, "stake_test1"):
+		return NetworkTestnet, true
+	case strings.HasPrefix(address, "addr1"), strings.HasPrefix(address, "stake1"):
+		return NetworkMainnet, true
+	default:
+		return "", false
+	}
+}
+
+// CheckNetwork returns an error wrapping ErrNetworkMismatch if the network of the transaction
+// body, or of any of its shelley output addresses, differs from network
+func (t Tx) CheckNetwork(network Network) error {
+	if len(t.Body.Network) > 0 && string(t.Body.Network) != "null" {
+		var got Network
+		if err := json.Unmarshal(t.Body.Network, &got); err != nil {
+			return fmt.Errorf("failed to decode network of tx, %v: %w", t.ID, err)
+		}
+		if got != network {
+			return fmt.Errorf("%w: tx %v is on %v; want %v", ErrNetworkMismatch, t.ID, got, network)
+		}
+	}
+	for _, output := range t.Body.Outputs {
+		if err := CheckAddressNetwork(output.Address, network); err != nil {
+			return fmt.Errorf("tx %v: %w", t.ID, err)
+		}
+	}
+	return nil
+}
+
+// CheckAddressNetwork returns an error wrapping ErrNetworkMismatch if address is a shelley
+// address belonging to a network other than network
+func CheckAddressNetwork(address string, network Network) error {
+	if got, ok := AddressNetwork(address); ok && got != network {
+		return fmt.Errorf("%w: address %v is on %v; want %v", ErrNetworkMismatch, address, got, network)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTx_CheckNetwork(t *testing.T) {
+	tests := map[string]struct {
+		Body string
+		Want error
+	}{
+		"no network": {
+			Body: `{"outputs":[{"address":"Ae2tdPwUPEZ4YjgvykNpoFeYUxoyhNj2kg8KfKWN2FizsSpLUPv68MpTVDo"}]}`,
+		},
+		"mainnet outputs": {
+			Body: `{"outputs":[{"address":"addr1vx2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzers66hrl8"}]}`,
+		},
+		"testnet output": {
+			Body: `{"outputs":[{"address":"addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz"}]}`,
+			Want: ErrNetworkMismatch,
+		},
+		"testnet body": {
+			Body: `{"network":"testnet"}`,
+			Want: ErrNetworkMismatch,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var tx Tx
+			if err := json.Unmarshal([]byte(tc.Body), &tx.Body); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := tx.CheckNetwork(NetworkMainnet), tc.Want; !errors.Is(got, want) {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
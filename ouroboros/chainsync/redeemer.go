@@ -0,0 +1,102 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RedeemerPurpose identifies what a redeemer is provided to validate
+type RedeemerPurpose string
+
+const (
+	RedeemerSpend       RedeemerPurpose = "spend"       // RedeemerSpend validates spending an input
+	RedeemerMint        RedeemerPurpose = "mint"        // RedeemerMint validates minting or burning under a policy
+	RedeemerCertificate RedeemerPurpose = "certificate" // RedeemerCertificate validates a certificate
+	RedeemerWithdrawal  RedeemerPurpose = "withdrawal"  // RedeemerWithdrawal validates a reward withdrawal
+)
+
+// redeemerPurposeOrder orders purposes by the tag used by the ledger
+var redeemerPurposeOrder = map[RedeemerPurpose]int{
+	RedeemerSpend:       0,
+	RedeemerMint:        1,
+	RedeemerCertificate: 2,
+	RedeemerWithdrawal:  3,
+}
+
+// ExecutionUnits is the execution budget of a script
+type ExecutionUnits struct {
+	Memory uint64 `json:"memory"`
+	Steps  uint64 `json:"steps"`
+}
+
+// Redeemer is a redeemer provided by the witness of a transaction.  Index refers to the
+// sorted inputs, policy ids, certificates, or reward accounts of the transaction, per Purpose.
+type Redeemer struct {
+	Purpose        RedeemerPurpose
+	Index          uint64
+	Data           string // Data is the hex encoded redeemer
+	ExecutionUnits ExecutionUnits
+}
+
+// ParsedRedeemers returns the redeemers of the transaction ordered by purpose, as tagged by
+// the ledger, and then by index
+func (t Tx) ParsedRedeemers() ([]Redeemer, error) {
+	if len(t.Witness.Redeemers) == 0 || string(t.Witness.Redeemers) == "null" {
+		return nil, nil
+	}
+
+	var raw map[string]struct {
+		Redeemer       string         `json:"redeemer"`
+		ExecutionUnits ExecutionUnits `json:"executionUnits"`
+	}
+	if err := json.Unmarshal(t.Witness.Redeemers, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode redeemers: %w", err)
+	}
+
+	redeemers := make([]Redeemer, 0, len(raw))
+	for pointer, r := range raw {
+		purpose, index, ok := strings.Cut(pointer, ":")
+		if !ok {
+			return nil, fmt.Errorf("failed to decode redeemers: invalid pointer, %v", pointer)
+		}
+		if _, ok := redeemerPurposeOrder[RedeemerPurpose(purpose)]; !ok {
+			return nil, fmt.Errorf("failed to decode redeemers: unknown purpose, %v", pointer)
+		}
+		i, err := strconv.ParseUint(index, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode redeemers: invalid index, %v: %w", pointer, err)
+		}
+		redeemers = append(redeemers, Redeemer{
+			Purpose:        RedeemerPurpose(purpose),
+			Index:          i,
+			Data:           r.Redeemer,
+			ExecutionUnits: r.ExecutionUnits,
+		})
+	}
+
+	sort.Slice(redeemers, func(i, j int) bool {
+		a, b := redeemers[i], redeemers[j]
+		if a.Purpose != b.Purpose {
+			return redeemerPurposeOrder[a.Purpose] < redeemerPurposeOrder[b.Purpose]
+		}
+		return a.Index < b.Index
+	})
+	return redeemers, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTx_ParsedRedeemers(t *testing.T) {
+	tests := map[string]struct {
+		Redeemers string
+		Want      []Redeemer
+		WantErr   bool
+	}{
+		"none": {},
+		"all purposes": {
+			Redeemers: `{
+				"withdrawal:0": {"redeemer":"d87980","executionUnits":{"memory":4,"steps":40}},
+				"spend:1": {"redeemer":"02","executionUnits":{"memory":4319292,"steps":1448909354}},
+				"certificate:0": {"redeemer":"d87a80","executionUnits":{"memory":3,"steps":30}},
+				"mint:0": {"redeemer":"01","executionUnits":{"memory":2,"steps":20}},
+				"spend:0": {"redeemer":"00","executionUnits":{"memory":1,"steps":10}}
+			}`,
+			Want: []Redeemer{
+				{Purpose: RedeemerSpend, Index: 0, Data: "00", ExecutionUnits: ExecutionUnits{Memory: 1, Steps: 10}},
+				{Purpose: RedeemerSpend, Index: 1, Data: "02", ExecutionUnits: ExecutionUnits{Memory: 4319292, Steps: 1448909354}},
+				{Purpose: RedeemerMint, Index: 0, Data: "01", ExecutionUnits: ExecutionUnits{Memory: 2, Steps: 20}},
+				{Purpose: RedeemerCertificate, Index: 0, Data: "d87a80", ExecutionUnits: ExecutionUnits{Memory: 3, Steps: 30}},
+				{Purpose: RedeemerWithdrawal, Index: 0, Data: "d87980", ExecutionUnits: ExecutionUnits{Memory: 4, Steps: 40}},
+			},
+		},
+		"unknown purpose": {
+			Redeemers: `{"vote:0":{"redeemer":"00","executionUnits":{"memory":1,"steps":1}}}`,
+			WantErr:   true,
+		},
+		"invalid index": {
+			Redeemers: `{"spend:x":{"redeemer":"00","executionUnits":{"memory":1,"steps":1}}}`,
+			WantErr:   true,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			tx := Tx{Witness: Witness{Redeemers: json.RawMessage(tc.Redeemers)}}
+			got, err := tx.ParsedRedeemers()
+			if tc.WantErr {
+				if err == nil {
+					t.Fatalf("got nil; want err")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if !reflect.DeepEqual(got, tc.Want) {
+				t.Fatalf("got %#v; want %#v", got, tc.Want)
+			}
+		})
+	}
+}
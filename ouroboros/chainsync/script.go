@@ -0,0 +1,325 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+// NativeScriptType identifies the clause of a NativeScript
+type NativeScriptType string
+
+const (
+	NativeScriptSignature NativeScriptType = "signature" // NativeScriptSignature requires a signature from KeyHash
+	NativeScriptAll       NativeScriptType = "all"       // NativeScriptAll requires all Scripts be satisfied
+	NativeScriptAny       NativeScriptType = "any"       // NativeScriptAny requires any of Scripts be satisfied
+	NativeScriptAtLeast   NativeScriptType = "atLeast"   // NativeScriptAtLeast requires Required of Scripts be satisfied
+	NativeScriptAfter     NativeScriptType = "after"     // NativeScriptAfter requires the slot be at or after Slot
+	NativeScriptBefore    NativeScriptType = "before"    // NativeScriptBefore requires the slot be before Slot
+)
+
+// NativeScript provides the clause tree of a native (timelock / multisig) script
+type NativeScript struct {
+	Type     NativeScriptType
+	KeyHash  string         // KeyHash for signature
+	Scripts  []NativeScript // Scripts for all, any, and atLeast
+	Required uint64         // Required number of Scripts for atLeast
+	Slot     uint64         // Slot for after and before
+}
+
+// IsSatisfied returns true if the script is satisfied by the signatories present at the slot
+func (s NativeScript) IsSatisfied(signatories map[string]bool, slot uint64) bool {
+	switch s.Type {
+	case NativeScriptSignature:
+		return signatories[s.KeyHash]
+	case NativeScriptAll:
+		for _, script := range s.Scripts {
+			if !script.IsSatisfied(signatories, slot) {
+				return false
+			}
+		}
+		return true
+	case NativeScriptAny:
+		for _, script := range s.Scripts {
+			if script.IsSatisfied(signatories, slot) {
+				return true
+			}
+		}
+		return false
+	case NativeScriptAtLeast:
+		var n uint64
+		for _, script := range s.Scripts {
+			if n >= s.Required {
+				break
+			}
+			if script.IsSatisfied(signatories, slot) {
+				n++
+			}
+		}
+		return n >= s.Required
+	case NativeScriptAfter:
+		return slot >= s.Slot
+	case NativeScriptBefore:
+		return slot < s.Slot
+	default:
+		return false
+	}
+}
+
+// IsSatisfiedBy returns true if the script is satisfied by the signatory key hashes at the
+// slot; see IsSatisfied
+func (s NativeScript) IsSatisfiedBy(signatories []string, slot uint64) bool {
+	present := make(map[string]bool, len(signatories))
+	for _, keyHash := range signatories {
+		present[keyHash] = true
+	}
+	return s.IsSatisfied(present, slot)
+}
+
+func (s NativeScript) MarshalJSON() ([]byte, error) {
+	switch s.Type {
+	case NativeScriptSignature:
+		return json.Marshal(s.KeyHash)
+	case NativeScriptAll, NativeScriptAny:
+		return json.Marshal(map[string][]NativeScript{string(s.Type): nonNil(s.Scripts)})
+	case NativeScriptAtLeast:
+		return json.Marshal(map[string][]NativeScript{strconv.FormatUint(s.Required, 10): nonNil(s.Scripts)})
+	case NativeScriptAfter:
+		return json.Marshal(map[string]uint64{"startsAt": s.Slot})
+	case NativeScriptBefore:
+		return json.Marshal(map[string]uint64{"expiresAt": s.Slot})
+	default:
+		return nil, fmt.Errorf("unable to marshal NativeScript: unknown type, %v", s.Type)
+	}
+}
+
+func (s *NativeScript) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var keyHash string
+		if err := json.Unmarshal(data, &keyHash); err != nil {
+			return fmt.Errorf("failed to unmarshal NativeScript: %w", err)
+		}
+		*s = NativeScript{Type: NativeScriptSignature, KeyHash: keyHash}
+		return nil
+	}
+
+	var clause map[string]json.RawMessage
+	if err := json.Unmarshal(data, &clause); err != nil {
+		return fmt.Errorf("failed to unmarshal NativeScript: %w", err)
+	}
+	if len(clause) != 1 {
+		return fmt.Errorf("failed to unmarshal NativeScript: expected exactly one clause, %v", string(data))
+	}
+
+	for key, value := range clause {
+		var script NativeScript
+		switch key {
+		case "all", "any":
+			script.Type = NativeScriptType(key)
+			if err := json.Unmarshal(value, &script.Scripts); err != nil {
+				return fmt.Errorf("failed to unmarshal NativeScript: %v: %w", key, err)
+			}
+		case "startsAt", "expiresAt":
+			script.Type = NativeScriptAfter
+			if key == "expiresAt" {
+				script.Type = NativeScriptBefore
+			}
+			if err := json.Unmarshal(value, &script.Slot); err != nil {
+				return fmt.Errorf("failed to unmarshal NativeScript: %v: %w", key, err)
+			}
+		default:
+			n, err := strconv.ParseUint(key, 10, 64)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal NativeScript: unknown clause, %v", key)
+			}
+			script.Type = NativeScriptAtLeast
+			script.Required = n
+			if err := json.Unmarshal(value, &script.Scripts); err != nil {
+				return fmt.Errorf("failed to unmarshal NativeScript: %v: %w", key, err)
+			}
+		}
+		*s = script
+	}
+
+	return nil
+}
+
+// NativeScripts returns the native scripts provided by the witness keyed by script hash
+func (w Witness) NativeScripts() (map[string]NativeScript, error) {
+	if len(w.Scripts) == 0 {
+		return nil, nil
+	}
+
+	var scripts map[string]struct {
+		Native *NativeScript `json:"native"`
+	}
+	if err := json.Unmarshal(w.Scripts, &scripts); err != nil {
+		return nil, fmt.Errorf("failed to decode witness scripts: %w", err)
+	}
+
+	natives := map[string]NativeScript{}
+	for hash, script := range scripts {
+		if script.Native != nil {
+			natives[hash] = *script.Native
+		}
+	}
+	return natives, nil
+}
+
+// RequiredScripts returns, sorted, the hashes of the scripts the transaction must provide that
+// can be determined from the transaction alone i.e. the policy ids of minted and burned assets.
+// Scripts locking spent inputs or script reward accounts require resolution and are excluded.
+func (t Tx) RequiredScripts() []string {
+	if t.Body.Mint == nil {
+		return nil
+	}
+
+	seen := map[string]struct{}{}
+	for assetID := range t.Body.Mint.Assets {
+		seen[assetID.PolicyID()] = struct{}{}
+	}
+
+	hashes := make([]string, 0, len(seen))
+	for hash := range seen {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+// HasAllRequiredScripts returns true if every script listed by RequiredScripts is provided by
+// the witness.  Scripts provided via reference inputs are not considered.
+func (t Tx) HasAllRequiredScripts() bool {
+	required := t.RequiredScripts()
+	if len(required) == 0 {
+		return true
+	}
+
+	var scripts map[string]json.RawMessage
+	if len(t.Witness.Scripts) > 0 {
+		if err := json.Unmarshal(t.Witness.Scripts, &scripts); err != nil {
+			return false
+		}
+	}
+	for _, hash := range required {
+		if _, ok := scripts[hash]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func nonNil(scripts []NativeScript) []NativeScript {
+	if scripts == nil {
+		return []NativeScript{}
+	}
+	return scripts
+}
+
+// ScriptLanguage identifies the language of a Script
+type ScriptLanguage string
+
+const (
+	ScriptNative   ScriptLanguage = "native"
+	ScriptPlutusV1 ScriptLanguage = "plutus:v1"
+	ScriptPlutusV2 ScriptLanguage = "plutus:v2"
+)
+
+// Script is a script as reported by ogmios e.g. in the script field of a TxOut
+type Script struct {
+	Language ScriptLanguage
+	Native   *NativeScript // Native for native scripts
+	Plutus   []byte        // Plutus contains the serialized plutus script
+}
+
+// ParseScript decodes a script of the form {"native":...} or {"plutus:v2":"<hex>"}
+func ParseScript(data json.RawMessage) (Script, error) {
+	var raw struct {
+		Native   *NativeScript `json:"native"`
+		PlutusV1 string        `json:"plutus:v1"`
+		PlutusV2 string        `json:"plutus:v2"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Script{}, fmt.Errorf("failed to decode script: %w", err)
+	}
+
+	switch {
+	case raw.Native != nil:
+		return Script{Language: ScriptNative, Native: raw.Native}, nil
+	case raw.PlutusV1 != "":
+		v, err := hex.DecodeString(raw.PlutusV1)
+		if err != nil {
+			return Script{}, fmt.Errorf("invalid plutus script: %w", err)
+		}
+		return Script{Language: ScriptPlutusV1, Plutus: v}, nil
+	case raw.PlutusV2 != "":
+		v, err := hex.DecodeString(raw.PlutusV2)
+		if err != nil {
+			return Script{}, fmt.Errorf("invalid plutus script: %w", err)
+		}
+		return Script{Language: ScriptPlutusV2, Plutus: v}, nil
+	default:
+		return Script{}, fmt.Errorf("unsupported script, %v", string(data))
+	}
+}
+
+// tagged returns the ledger script tag along with the serialized script
+func (s Script) tagged() (byte, []byte, error) {
+	switch s.Language {
+	case ScriptNative:
+		if s.Native == nil {
+			return 0, nil, fmt.Errorf("native script missing")
+		}
+		v, err := s.Native.cborValue()
+		if err != nil {
+			return 0, nil, err
+		}
+		encoded, err := cbor.Marshal(v)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to encode native script: %w", err)
+		}
+		return 0, encoded, nil
+	case ScriptPlutusV1:
+		return 1, s.Plutus, nil
+	case ScriptPlutusV2:
+		return 2, s.Plutus, nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported script language, %v", s.Language)
+	}
+}
+
+// Hash returns the hex encoded blake2b-224 script hash, as used by policy ids and script
+// addresses
+func (s Script) Hash() (string, error) {
+	tag, data, err := s.tagged()
+	if err != nil {
+		return "", err
+	}
+
+	h, err := blake2b.New(28, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash script: %w", err)
+	}
+	h.Write([]byte{tag})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
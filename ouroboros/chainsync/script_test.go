@@ -0,0 +1,245 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainsync
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+func TestNativeScript_JSON(t *testing.T) {
+	data := []byte(`{"any":[{"all":["a",{"startsAt":100}]},{"2":["b","c",{"expiresAt":200}]}]}`)
+
+	var got NativeScript
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	want := NativeScript{
+		Type: NativeScriptAny,
+		Scripts: []NativeScript{
+			{
+				Type: NativeScriptAll,
+				Scripts: []NativeScript{
+					{Type: NativeScriptSignature, KeyHash: "a"},
+					{Type: NativeScriptAfter, Slot: 100},
+				},
+			},
+			{
+				Type:     NativeScriptAtLeast,
+				Required: 2,
+				Scripts: []NativeScript{
+					{Type: NativeScriptSignature, KeyHash: "b"},
+					{Type: NativeScriptSignature, KeyHash: "c"},
+					{Type: NativeScriptBefore, Slot: 200},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := string(encoded), string(data); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestNativeScript_IsSatisfied(t *testing.T) {
+	var script NativeScript
+	data := []byte(`{"any":[{"all":["a",{"startsAt":100}]},{"2":["b","c",{"expiresAt":200}]}]}`)
+	if err := json.Unmarshal(data, &script); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	tests := map[string]struct {
+		Signatories map[string]bool
+		Slot        uint64
+		Want        bool
+	}{
+		"none": {
+			Slot: 150,
+			Want: false,
+		},
+		"a after start": {
+			Signatories: map[string]bool{"a": true},
+			Slot:        100,
+			Want:        true,
+		},
+		"a before start": {
+			Signatories: map[string]bool{"a": true},
+			Slot:        99,
+			Want:        false,
+		},
+		"b and c": {
+			Signatories: map[string]bool{"b": true, "c": true},
+			Slot:        500,
+			Want:        true,
+		},
+		"b before expiry": {
+			Signatories: map[string]bool{"b": true},
+			Slot:        199,
+			Want:        true,
+		},
+		"b at expiry": {
+			Signatories: map[string]bool{"b": true},
+			Slot:        200,
+			Want:        false,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			if got := script.IsSatisfied(tc.Signatories, tc.Slot); got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		if got := (NativeScript{Type: NativeScriptAll}).IsSatisfied(nil, 0); !got {
+			t.Fatalf("got false; want true")
+		}
+		if got := (NativeScript{Type: NativeScriptAny}).IsSatisfied(nil, 0); got {
+			t.Fatalf("got true; want false")
+		}
+		if got := (NativeScript{Type: NativeScriptAtLeast}).IsSatisfied(nil, 0); !got {
+			t.Fatalf("got false; want true")
+		}
+	})
+}
+
+func TestNativeScript_IsSatisfiedBy(t *testing.T) {
+	var script NativeScript
+	data := []byte(`{"all":[{"any":["a","b"]},{"1":["c","d"]},{"0":[]},{"startsAt":100},{"expiresAt":200}]}`)
+	if err := json.Unmarshal(data, &script); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	tests := map[string]struct {
+		Signatories []string
+		Slot        uint64
+		Want        bool
+	}{
+		"at start": {
+			Signatories: []string{"a", "c"},
+			Slot:        100,
+			Want:        true,
+		},
+		"before start": {
+			Signatories: []string{"a", "c"},
+			Slot:        99,
+			Want:        false,
+		},
+		"before expiry": {
+			Signatories: []string{"b", "d"},
+			Slot:        199,
+			Want:        true,
+		},
+		"at expiry": {
+			Signatories: []string{"b", "d"},
+			Slot:        200,
+			Want:        false,
+		},
+		"missing atLeast": {
+			Signatories: []string{"a", "b"},
+			Slot:        150,
+			Want:        false,
+		},
+		"missing any": {
+			Signatories: []string{"c", "d"},
+			Slot:        150,
+			Want:        false,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			if got := script.IsSatisfiedBy(tc.Signatories, tc.Slot); got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+		})
+	}
+}
+
+func TestWitness_NativeScripts(t *testing.T) {
+	witness := Witness{
+		Scripts: json.RawMessage(`{"h1":{"native":"a"},"h2":{"plutus:v1":"4e4d01"}}`),
+	}
+
+	got, err := witness.NativeScripts()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want := map[string]NativeScript{
+		"h1": {Type: NativeScriptSignature, KeyHash: "a"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+}
+
+func TestTx_HasAllRequiredScripts(t *testing.T) {
+	mint := &Value{
+		Assets: map[AssetID]num.Int{
+			"p2.6e6674": num.Int64(1),
+			"p1.6e6674": num.Int64(-1),
+			"p1.6f7468": num.Int64(1),
+		},
+	}
+
+	tests := map[string]struct {
+		Tx   Tx
+		Want bool
+	}{
+		"no mint": {
+			Tx:   Tx{},
+			Want: true,
+		},
+		"all provided": {
+			Tx: Tx{
+				Body:    TxBody{Mint: mint},
+				Witness: Witness{Scripts: json.RawMessage(`{"p1":{"native":"a"},"p2":{"plutus:v2":"4d01"}}`)},
+			},
+			Want: true,
+		},
+		"missing": {
+			Tx: Tx{
+				Body:    TxBody{Mint: mint},
+				Witness: Witness{Scripts: json.RawMessage(`{"p1":{"native":"a"}}`)},
+			},
+			Want: false,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			if got, want := tc.Tx.HasAllRequiredScripts(), tc.Want; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+
+	if got, want := (Tx{Body: TxBody{Mint: mint}}).RequiredScripts(), []string{"p1", "p2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
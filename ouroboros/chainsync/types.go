@@ -19,8 +19,12 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -28,13 +32,16 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/buger/jsonparser"
 	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
 
 	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
 )
 
 var (
-	bNil       = []byte("nil")
+	bNil  = []byte("nil")
+	bNull = []byte("null")
 )
 
 type AssetID string
@@ -88,12 +95,159 @@ func (a AssetID) PolicyID() string {
 	return s // Assets with empty-string name come back as just the policy ID
 }
 
+// SplitAssetID splits an asset id into its hex encoded policy id and asset name.  Both the
+// ogmios form, policy.name, and the concatenated form, policy followed directly by the name,
+// are accepted.  The ada pseudo asset ids, "ada" and "lovelace", return an empty policy and name.
+func SplitAssetID(id string) (policy, name string) {
+	switch {
+	case id == "ada" || id == "lovelace":
+		return "", ""
+	case strings.Contains(id, "."):
+		return AssetID(id).PolicyID(), AssetID(id).AssetName()
+	case len(id) > 56:
+		return id[:56], id[56:]
+	default:
+		return id, ""
+	}
+}
+
 type Block struct {
 	Body       []Tx        `json:"body,omitempty"       dynamodbav:"body,omitempty"`
 	Header     BlockHeader `json:"header,omitempty"     dynamodbav:"header,omitempty"`
 	HeaderHash string      `json:"headerHash,omitempty" dynamodbav:"headerHash,omitempty"`
 }
 
+// IssuerCounter returns the operational certificate counter of the block issuer or 0 if
+// the header carries no operational certificate e.g. byron blocks
+func (b Block) IssuerCounter() uint64 {
+	if count, ok := b.Header.OpCert["count"].(float64); ok {
+		return uint64(count)
+	}
+	return 0
+}
+
+// Equal returns true if b and other hold the same block.  Blocks are compared by their json
+// form so that map valued fields e.g. values, scripts, and datums, are compared without
+// regard to key order.
+func (b Block) Equal(other Block) bool {
+	left, err := canonicalJSON(b)
+	if err != nil {
+		return false
+	}
+	right, err := canonicalJSON(other)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// canonicalJSON decodes the json encoding of v into generic maps, slices, and json.Number
+func canonicalJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var canonical interface{}
+	if err := decoder.Decode(&canonical); err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+	return canonical, nil
+}
+
+// DecodeBlockHeader decodes only the header and header hash of the json encoded Block,
+// skipping over, rather than decoding, the transactions in its body.  Consumers that ignore
+// transactions can use DecodeBlockHeader to avoid the bulk of the cost of decoding blocks.
+func DecodeBlockHeader(data []byte) (*Block, error) {
+	var block Block
+	err := jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, _ int) error {
+		switch string(key) {
+		case "header":
+			if err := json.Unmarshal(value, &block.Header); err != nil {
+				return fmt.Errorf("failed to decode header: %w", err)
+			}
+		case "headerHash":
+			headerHash, err := jsonparser.ParseString(value)
+			if err != nil {
+				return fmt.Errorf("failed to decode headerHash: %w", err)
+			}
+			block.HeaderHash = headerHash
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode block header: %w", err)
+	}
+	return &block, nil
+}
+
+// BlockTxIDs returns the ids of the transactions in the json encoded Block, in block order,
+// without decoding the transactions themselves
+func BlockTxIDs(raw []byte) ([]string, error) {
+	var (
+		ids     []string
+		scanErr error
+	)
+	_, err := jsonparser.ArrayEach(raw, func(value []byte, _ jsonparser.ValueType, _ int, _ error) {
+		if scanErr != nil {
+			return
+		}
+		id, err := jsonparser.GetString(value, "id")
+		if err != nil {
+			scanErr = fmt.Errorf("failed to decode tx id: %w", err)
+			return
+		}
+		ids = append(ids, id)
+	}, "body")
+	if errors.Is(err, jsonparser.KeyPathNotFoundError) {
+		return nil, nil // blocks without transactions may omit body
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode block body: %w", err)
+	}
+	if scanErr != nil {
+		return nil, scanErr
+	}
+	return ids, nil
+}
+
+// BlockEconomics summarizes the value moved by the transactions of a block
+type BlockEconomics struct {
+	Fees        num.Int // Fees paid by all transactions
+	Minted      Value   // Minted assets; positive amounts
+	Burned      Value   // Burned assets; positive amounts
+	Withdrawals num.Int // Withdrawals from reward accounts
+}
+
+// EconomicSummary totals the fees, mints, burns, and withdrawals of all transactions in the block
+func (b Block) EconomicSummary() BlockEconomics {
+	summary := BlockEconomics{
+		Minted: Value{Assets: map[AssetID]num.Int{}},
+		Burned: Value{Assets: map[AssetID]num.Int{}},
+	}
+	for _, tx := range b.Body {
+		summary.Fees = summary.Fees.Add(tx.Body.Fee)
+		for _, amount := range tx.Body.Withdrawals {
+			summary.Withdrawals = summary.Withdrawals.Add(num.Int64(amount))
+		}
+		if tx.Body.Mint == nil {
+			continue
+		}
+		for assetID, amount := range tx.Body.Mint.Assets {
+			switch amount.BigInt().Sign() {
+			case 1:
+				summary.Minted.Assets[assetID] = summary.Minted.Assets[assetID].Add(amount)
+			case -1:
+				summary.Burned.Assets[assetID] = summary.Burned.Assets[assetID].Sub(amount)
+			}
+		}
+	}
+	return summary
+}
+
 type BlockHeader struct {
 	BlockHash       string                 `json:"blockHash,omitempty"       dynamodbav:"blockHash,omitempty"`
 	BlockHeight     uint64                 `json:"blockHeight,omitempty"     dynamodbav:"blockHeight,omitempty"`
@@ -109,6 +263,13 @@ type BlockHeader struct {
 	Slot            uint64                 `json:"slot,omitempty"            dynamodbav:"slot,omitempty"`
 }
 
+// HasLeaderValue returns true if the header carries a leader vrf output; the field is absent
+// from byron headers and from babbage headers, which replace nonce and leaderValue with a
+// single vrf output
+func (h BlockHeader) HasLeaderValue() bool {
+	return len(h.LeaderValue) > 0
+}
+
 type IntersectionFound struct {
 	Point Point
 	Tip   Point
@@ -127,6 +288,11 @@ const (
 
 var Origin = PointString("origin").Point()
 
+// OriginPoints returns the points required to intersect the chain at its origin
+func OriginPoints() Points {
+	return Points{Origin}
+}
+
 type PointString string
 
 func (p PointString) Point() Point {
@@ -179,6 +345,33 @@ func (pp Points) String() string {
 	return strings.Join(ss, ", ")
 }
 
+// Dedup returns the points with duplicates removed, keeping the first occurrence of each.
+// Block points are considered duplicates if they share both slot and hash.
+func (pp Points) Dedup() Points {
+	type key struct {
+		s    PointString
+		slot uint64
+		hash string
+	}
+
+	var (
+		seen   = map[key]struct{}{}
+		points = make(Points, 0, len(pp))
+	)
+	for _, p := range pp {
+		k := key{s: p.pointString}
+		if ps := p.pointStruct; ps != nil {
+			k = key{slot: ps.Slot, hash: ps.Hash}
+		}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		points = append(points, p)
+	}
+	return points
+}
+
 func (pp Points) Len() int      { return len(pp) }
 func (pp Points) Swap(i, j int) { pp[i], pp[j] = pp[j], pp[i] }
 func (pp Points) Less(i, j int) bool {
@@ -216,6 +409,8 @@ func (p Point) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) erro
 			return fmt.Errorf("failed to marshal point struct: %w", err)
 		}
 		item.M = m
+	case 0:
+		item.NULL = aws.Bool(true) // zero Point
 	default:
 		return fmt.Errorf("unable to unmarshal Point: unknown type")
 	}
@@ -241,6 +436,8 @@ func (p Point) MarshalJSON() ([]byte, error) {
 		return json.Marshal(p.pointString)
 	case PointTypeStruct:
 		return json.Marshal(p.pointStruct)
+	case 0:
+		return bNull, nil // zero Point
 	default:
 		return nil, fmt.Errorf("unable to unmarshal Point: unknown type")
 	}
@@ -294,6 +491,9 @@ func (p *Point) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) e
 
 func (p *Point) UnmarshalJSON(data []byte) error {
 	switch {
+	case len(data) == 0 || bytes.Equal(data, bNull):
+		*p = Point{}
+
 	case data[0] == '"':
 		var s string
 		if err := json.Unmarshal(data, &s); err != nil {
@@ -384,6 +584,15 @@ type Result struct {
 	RollBackward         *RollBackward         `json:",omitempty" dynamodbav:",omitempty"`
 }
 
+// IntersectionNotFoundTip returns the tip reported when FindIntersect fails
+// to locate any of the requested points
+func (r Result) IntersectionNotFoundTip() (*PointStruct, bool) {
+	if r.IntersectionNotFound == nil {
+		return nil, false
+	}
+	return r.IntersectionNotFound.Tip.PointStruct()
+}
+
 type Response struct {
 	Type        string          `json:"type,omitempty"        dynamodbav:"type,omitempty"`
 	Version     string          `json:"version,omitempty"     dynamodbav:"version,omitempty"`
@@ -393,6 +602,13 @@ type Response struct {
 	Reflection  json.RawMessage `json:"reflection,omitempty"  dynamodbav:"reflection,omitempty"`
 }
 
+// HasReflection returns true if the response echoes the mirror of a request.  An absent
+// reflection and an explicit null both return false, but remain distinguishable as nil
+// and null respectively.
+func (r Response) HasReflection() bool {
+	return len(r.Reflection) > 0 && !bytes.Equal(r.Reflection, bNull)
+}
+
 type Tx struct {
 	ID          string          `json:"id,omitempty"       dynamodbav:"id,omitempty"`
 	InputSource string          `json:"inputSource,omitempty"  dynamodbav:"inputSource,omitempty"`
@@ -403,8 +619,277 @@ type Tx struct {
 	Raw string `json:"raw,omitempty" dynamodbav:"raw,omitempty"`
 }
 
+// CollateralBalance returns the value provided by the collateral inputs, the value returned via
+// collateralReturn, and the total collateral consumed should phase-2 validation fail.  provided
+// is derived from total and returned; if the transaction omits totalCollateral, total is zero
+// and provided cannot be determined without resolving the collateral inputs.
+func (t Tx) CollateralBalance() (provided Value, returned Value, total Value) {
+	if r := t.Body.CollateralReturn; r != nil {
+		returned = r.Value
+	}
+	if tc := t.Body.TotalCollateral; tc != nil {
+		total = Value{Coins: num.Int64(*tc)}
+	}
+	return Add(total, returned), returned, total
+}
+
+// ConservesValue checks the transaction preserves value given the TxOut of each input in
+// utxos, returning the imbalance, consumed less produced, if any.  Ordinarily, inputs,
+// withdrawals, and mints are consumed while outputs and the fee are produced.  Where phase-2
+// validation failed, InputSource collaterals, collateral inputs are consumed while the
+// collateral return and total collateral are produced.  Deposits and refunds from
+// certificates are not accounted for and surface as an imbalance, as do inputs missing from
+// utxos.
+func (t Tx) ConservesValue(utxos map[TxID]TxOut) (bool, Value) {
+	var consumed, produced Value
+	consume := func(txIns []TxIn) {
+		for _, txIn := range txIns {
+			if txOut, ok := utxos[txIn.TxID()]; ok {
+				consumed = Add(consumed, txOut.Value)
+			}
+		}
+	}
+
+	if t.InputSource == "collaterals" {
+		consume(t.Body.Collaterals)
+		if r := t.Body.CollateralReturn; r != nil {
+			produced = Add(produced, r.Value)
+		}
+		if tc := t.Body.TotalCollateral; tc != nil {
+			produced = Add(produced, Value{Coins: num.Int64(*tc)})
+		} else {
+			// without totalCollateral, all collateral not returned is forfeit
+			produced = Add(produced, Value{Coins: Subtract(consumed, produced).Coins})
+		}
+	} else {
+		consume(t.Body.Inputs)
+		for _, amount := range t.Body.Withdrawals {
+			consumed = Add(consumed, Value{Coins: num.Int64(amount)})
+		}
+		if t.Body.Mint != nil {
+			consumed = Add(consumed, *t.Body.Mint)
+		}
+		for _, txOut := range t.Body.Outputs {
+			produced = Add(produced, txOut.Value)
+		}
+		produced = Add(produced, Value{Coins: t.Body.Fee})
+	}
+
+	imbalance := Subtract(consumed, produced)
+	return Equals(imbalance, Value{}), imbalance
+}
+
+// TxSummary is a compact overview of a transaction
+type TxSummary struct {
+	ID               string
+	Inputs           int                 // Inputs spent; collateral inputs for phase-2 failures
+	Outputs          int                 // Outputs produced; the collateral return for phase-2 failures
+	Fee              num.Int             // Fee declared by the transaction
+	Output           Value               // Output is the total value of Outputs
+	NetMint          map[AssetID]num.Int // NetMint is the amount minted, negative if burned, per asset; nil for phase-2 failures
+	PhaseTwoFailure  bool                // PhaseTwoFailure is true if script validation failed and collateral was taken
+	CertificateTypes []string            // CertificateTypes present, in order of first appearance
+	HasMetadata      bool
+}
+
+// Summary returns an overview of the transaction such as rendered by block explorers
+func (t Tx) Summary() TxSummary {
+	summary := TxSummary{
+		ID:              t.ID,
+		Fee:             t.Body.Fee,
+		PhaseTwoFailure: t.InputSource == "collaterals",
+		HasMetadata:     len(t.Metadata) > 0 && !bytes.Equal(t.Metadata, bNull),
+	}
+
+	if summary.PhaseTwoFailure {
+		summary.Inputs = len(t.Body.Collaterals)
+		if r := t.Body.CollateralReturn; r != nil {
+			summary.Outputs = 1
+			summary.Output = r.Value
+		}
+	} else {
+		summary.Inputs = len(t.Body.Inputs)
+		summary.Outputs = len(t.Body.Outputs)
+		for _, txOut := range t.Body.Outputs {
+			summary.Output = Add(summary.Output, txOut.Value)
+		}
+		if t.Body.Mint != nil && len(t.Body.Mint.Assets) > 0 {
+			summary.NetMint = map[AssetID]num.Int{}
+			for assetID, amount := range t.Body.Mint.Assets {
+				summary.NetMint[assetID] = summary.NetMint[assetID].Add(amount)
+			}
+		}
+	}
+
+	seen := map[string]struct{}{}
+	for _, cert := range t.Body.Certificates {
+		if _, ok := seen[cert.Type]; ok {
+			continue
+		}
+		seen[cert.Type] = struct{}{}
+		summary.CertificateTypes = append(summary.CertificateTypes, cert.Type)
+	}
+
+	return summary
+}
+
+// AllReferencedTxIns returns the distinct reference, spent, and collateral inputs of the
+// transaction i.e. every TxIn that must be resolved to evaluate the transaction
+func (t Tx) AllReferencedTxIns() []TxIn {
+	var (
+		seen  = map[TxIn]struct{}{}
+		txIns []TxIn
+	)
+	for _, items := range [][]TxIn{t.Body.References, t.Body.Inputs, t.Body.Collaterals} {
+		for _, txIn := range items {
+			if _, ok := seen[txIn]; ok {
+				continue
+			}
+			seen[txIn] = struct{}{}
+			txIns = append(txIns, txIn)
+		}
+	}
+	return txIns
+}
+
+// MissingDatums returns, in order of first appearance, the distinct datum hashes of the
+// outputs of the transaction for which no preimage is present in the witness datums.  Datums
+// of spent inputs can only be determined once the inputs are resolved; see MissingInputDatums.
+func (t Tx) MissingDatums() []string {
+	outputs := t.Body.Outputs
+	if r := t.Body.CollateralReturn; r != nil {
+		outputs = append(outputs[:len(outputs):len(outputs)], *r)
+	}
+	return t.missingDatums(outputs)
+}
+
+// MissingInputDatums returns, in order of first appearance, the distinct datum hashes of the
+// spent inputs, resolved via utxos, for which no preimage is present in the witness datums.
+// Inputs not found in utxos are ignored.
+func (t Tx) MissingInputDatums(utxos map[TxID]TxOut) []string {
+	var outputs []TxOut
+	for _, txIn := range t.Body.Inputs {
+		if txOut, ok := utxos[txIn.TxID()]; ok {
+			outputs = append(outputs, txOut)
+		}
+	}
+	return t.missingDatums(outputs)
+}
+
+func (t Tx) missingDatums(outputs []TxOut) []string {
+	var (
+		seen    = map[string]struct{}{}
+		missing []string
+	)
+	for _, txOut := range outputs {
+		hash := txOut.DatumHash
+		if hash == "" {
+			continue
+		}
+		if _, ok := t.Witness.Datums[hash]; ok {
+			continue
+		}
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+		missing = append(missing, hash)
+	}
+	return missing
+}
+
+// ResolvedInputs pairs each input of the transaction with the TxOut it refers to and how it
+// is used by the transaction.  An input used in more than one way, e.g. spent and used as
+// collateral, is listed once per use.  TxOut is nil for inputs not found in utxos.
+func (t Tx) ResolvedInputs(utxos map[TxID]TxOut) []ResolvedInput {
+	var inputs []ResolvedInput
+	add := func(kind InputKind, txIns []TxIn) {
+		for _, txIn := range txIns {
+			input := ResolvedInput{TxIn: txIn, Kind: kind}
+			if txOut, ok := utxos[txIn.TxID()]; ok {
+				input.TxOut = &txOut
+			}
+			inputs = append(inputs, input)
+		}
+	}
+	add(InputSpent, t.Body.Inputs)
+	add(InputReference, t.Body.References)
+	add(InputCollateral, t.Body.Collaterals)
+	return inputs
+}
+
+// RequiredSigners returns the sorted, distinct key hashes that may be required to sign the
+// transaction: the required extra signatures, the signature clauses of native scripts in the
+// witness, and the payment keys of any resolved spent or collateral inputs locked by a key
+func (t Tx) RequiredSigners(resolved ...ResolvedInput) []string {
+	seen := map[string]struct{}{}
+	for _, keyHash := range t.Body.RequiredExtraSignatures {
+		seen[keyHash] = struct{}{}
+	}
+
+	var visit func(script NativeScript)
+	visit = func(script NativeScript) {
+		if script.Type == NativeScriptSignature {
+			seen[script.KeyHash] = struct{}{}
+		}
+		for _, s := range script.Scripts {
+			visit(s)
+		}
+	}
+	if scripts, err := t.Witness.NativeScripts(); err == nil {
+		for _, script := range scripts {
+			visit(script)
+		}
+	}
+
+	for _, input := range resolved {
+		if input.Kind == InputReference || input.TxOut == nil {
+			continue
+		}
+		if keyHash, ok := paymentKeyHash(input.TxOut.Address); ok {
+			seen[keyHash] = struct{}{}
+		}
+	}
+
+	signers := make([]string, 0, len(seen))
+	for keyHash := range seen {
+		signers = append(signers, keyHash)
+	}
+	sort.Strings(signers)
+	return signers
+}
+
+// InputKind describes how a transaction uses one of its inputs
+type InputKind int
+
+const (
+	InputSpent      InputKind = 1 // InputSpent is consumed by the transaction
+	InputReference  InputKind = 2 // InputReference is read, but not consumed, by the transaction
+	InputCollateral InputKind = 3 // InputCollateral is consumed only if phase-2 validation fails
+)
+
+func (k InputKind) String() string {
+	switch k {
+	case InputSpent:
+		return "spent"
+	case InputReference:
+		return "reference"
+	case InputCollateral:
+		return "collateral"
+	default:
+		return "unknown"
+	}
+}
+
+// ResolvedInput describes an input along with how it is used by the transaction
+type ResolvedInput struct {
+	TxIn  TxIn
+	TxOut *TxOut
+	Kind  InputKind
+}
+
 type TxBody struct {
-	Certificates            []json.RawMessage `json:"certificates,omitempty"            dynamodbav:"certificates,omitempty"`
+	Certificates            []Certificate     `json:"certificates,omitempty"            dynamodbav:"certificates,omitempty"`
 	Collaterals             []TxIn            `json:"collaterals,omitempty"             dynamodbav:"collaterals,omitempty"`
 	Fee                     num.Int           `json:"fee,omitempty"                     dynamodbav:"fee,omitempty"`
 	Inputs                  []TxIn            `json:"inputs,omitempty"                  dynamodbav:"inputs,omitempty"`
@@ -428,6 +913,26 @@ func NewTxID(txHash string, index int) TxID {
 	return TxID(txHash + "#" + strconv.Itoa(index))
 }
 
+// ComputeTxID returns the id of the hex encoded transaction, the blake2b-256 hash of its
+// body.  As the id is computed for the transaction as a whole, it has no output index.
+func ComputeTxID(cborHex string) (TxID, error) {
+	data, err := hex.DecodeString(cborHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute tx id: invalid hex: %w", err)
+	}
+
+	var items []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &items); err != nil {
+		return "", fmt.Errorf("failed to compute tx id: invalid cbor: %w", err)
+	}
+	if len(items) == 0 {
+		return "", fmt.Errorf("failed to compute tx id: missing tx body")
+	}
+
+	hash := blake2b.Sum256(items[0])
+	return TxID(hex.EncodeToString(hash[:])), nil
+}
+
 func (t TxID) String() string {
 	return string(t)
 }
@@ -551,6 +1056,52 @@ type Witness struct {
 	Signatures map[string]string `json:"signatures,omitempty" dynamodbav:"signatures,omitempty"`
 }
 
+// BootstrapWitness is a byron-style witness, found in Witness.Bootstrap, produced when
+// spending from byron addresses.  ChainCode and AddressAttributes are only populated for
+// such witnesses and may be empty when the address carries no attributes.
+type BootstrapWitness struct {
+	Key               string `json:"key,omitempty"`
+	Signature         string `json:"signature,omitempty"`
+	ChainCode         string `json:"chainCode,omitempty"`
+	AddressAttributes string `json:"addressAttributes,omitempty"`
+}
+
+// ChainCodeBytes returns the decoded chain code of the extended verification key
+func (b BootstrapWitness) ChainCodeBytes() ([]byte, error) {
+	return decodeHexOrBase64(b.ChainCode)
+}
+
+// AddressAttributesBytes returns the decoded cbor attributes of the byron address
+func (b BootstrapWitness) AddressAttributesBytes() ([]byte, error) {
+	return decodeHexOrBase64(b.AddressAttributes)
+}
+
+// BootstrapWitnesses decodes the byron-style witnesses of the transaction
+func (w Witness) BootstrapWitnesses() ([]BootstrapWitness, error) {
+	var witnesses []BootstrapWitness
+	for _, raw := range w.Bootstrap {
+		var witness BootstrapWitness
+		if err := json.Unmarshal(raw, &witness); err != nil {
+			return nil, fmt.Errorf("failed to decode bootstrap witness: %w", err)
+		}
+		witnesses = append(witnesses, witness)
+	}
+	return witnesses, nil
+}
+
+// decodeHexOrBase64 decodes s as hex, falling back to base64, as ogmios has encoded bytes
+// both ways across versions
+func decodeHexOrBase64(s string) ([]byte, error) {
+	if data, err := hex.DecodeString(s); err == nil {
+		return data, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode string %v: %w", s, err)
+	}
+	return data, nil
+}
+
 type ValidityInterval struct {
 	InvalidBefore    uint64 `json:"invalidBefore,omitempty"    dynamodbav:"invalidBefore,omitempty"`
 	InvalidHereafter uint64 `json:"invalidHereafter,omitempty" dynamodbav:"invalidHereafter,omitempty"`
@@ -561,6 +1112,229 @@ type Value struct {
 	Assets map[AssetID]num.Int `json:"assets,omitempty" dynamodbav:"assets,omitempty"`
 }
 
+// Coin returns a copy of the lovelace held by the value; zero when coins were omitted e.g. mints.
+// Not to be confused with the Coin type, an entry of a flattened Value.
+func (v Value) Coin() *big.Int {
+	return new(big.Int).Set(v.Coins.BigInt())
+}
+
+// IsADAOnly returns true if the value holds no native assets; assets with a zero amount are ignored
+func (v Value) IsADAOnly() bool {
+	for _, amt := range v.Assets {
+		if amt.BigInt().Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAsset returns true if the value holds a non-zero amount of the given asset; assetName is hex encoded
+func (v Value) HasAsset(policyID, assetName string) bool {
+	assetID := AssetID(policyID)
+	if assetName != "" {
+		assetID = AssetID(policyID + "." + assetName)
+	}
+	amt, ok := v.Assets[assetID]
+	return ok && amt.BigInt().Sign() != 0
+}
+
+// Policies returns the sorted, distinct policy ids of the assets held by the value; assets
+// with a zero amount are ignored
+func (v Value) Policies() []string {
+	seen := map[string]struct{}{}
+	var policies []string
+	for assetID, amt := range v.Assets {
+		if amt.BigInt().Sign() == 0 {
+			continue
+		}
+		policyID := assetID.PolicyID()
+		if _, ok := seen[policyID]; !ok {
+			seen[policyID] = struct{}{}
+			policies = append(policies, policyID)
+		}
+	}
+	sort.Strings(policies)
+	return policies
+}
+
+// AssetsUnder returns copies of the non-zero amounts held under the policy keyed by hex
+// encoded asset name; the empty asset name is keyed by ""
+func (v Value) AssetsUnder(policyID string) map[string]*big.Int {
+	assets := map[string]*big.Int{}
+	for assetID, amt := range v.Assets {
+		if amt.BigInt().Sign() != 0 && assetID.PolicyID() == policyID {
+			assets[assetID.AssetName()] = new(big.Int).Set(amt.BigInt())
+		}
+	}
+	return assets
+}
+
+// AssetCount returns the number of distinct native assets, excluding ada, held by the value
+func (v Value) AssetCount() int {
+	var n int
+	for _, amt := range v.Assets {
+		if amt.BigInt().Sign() != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// AdaAssetID identifies lovelace within a flattened Value
+const AdaAssetID AssetID = "ada"
+
+// Coin is a single entry of a flattened Value, as returned by Flatten; the entry for lovelace
+// has the AssetID, AdaAssetID.  The lovelace of a Value is available directly via Value.Coin.
+type Coin struct {
+	AssetID AssetID `json:"assetId" dynamodbav:"assetId"`
+	Amount  num.Int `json:"amount"  dynamodbav:"amount"`
+}
+
+// PolicyID returns the hex encoded policy id of the asset; empty for AdaAssetID
+func (c Coin) PolicyID() string {
+	policy, _ := SplitAssetID(string(c.AssetID))
+	return policy
+}
+
+// AssetName returns the hex encoded asset name of the asset; empty for AdaAssetID
+func (c Coin) AssetName() string {
+	_, name := SplitAssetID(string(c.AssetID))
+	return name
+}
+
+// Flatten returns one Coin per non-zero amount held by the value; lovelace comes first, as
+// AdaAssetID, followed by the assets sorted by asset id
+func (v Value) Flatten() []Coin {
+	var coins []Coin
+	if v.Coins.BigInt().Sign() != 0 {
+		coins = append(coins, Coin{AssetID: AdaAssetID, Amount: v.Coins})
+	}
+
+	assetIDs := make([]string, 0, len(v.Assets))
+	for assetID, amt := range v.Assets {
+		if amt.BigInt().Sign() != 0 {
+			assetIDs = append(assetIDs, string(assetID))
+		}
+	}
+	sort.Strings(assetIDs)
+
+	for _, assetID := range assetIDs {
+		coins = append(coins, Coin{AssetID: AssetID(assetID), Amount: v.Assets[AssetID(assetID)]})
+	}
+	return coins
+}
+
+// ValueFromCoins rebuilds a Value from a flattened list; amounts of repeated asset ids are summed
+func ValueFromCoins(coins []Coin) Value {
+	var value Value
+	for _, coin := range coins {
+		if coin.AssetID == AdaAssetID || coin.AssetID == "lovelace" {
+			value.Coins = value.Coins.Add(coin.Amount)
+			continue
+		}
+		if value.Assets == nil {
+			value.Assets = map[AssetID]num.Int{}
+		}
+		value.Assets[coin.AssetID] = value.Assets[coin.AssetID].Add(coin.Amount)
+	}
+	return value
+}
+
+// TrySubtract returns v - other, omitting assets that reach zero, provided v covers other;
+// ok is false, and the returned Value empty, if any amount would become negative
+func (v Value) TrySubtract(other Value) (Value, bool) {
+	coins := new(big.Int).Sub(v.Coins.BigInt(), other.Coins.BigInt())
+	if coins.Sign() < 0 {
+		return Value{}, false
+	}
+
+	assets := map[AssetID]num.Int{}
+	for assetID, amt := range v.Assets {
+		if amt.BigInt().Sign() != 0 {
+			assets[assetID] = amt
+		}
+	}
+	for assetID, amt := range other.Assets {
+		remaining := new(big.Int).Sub(assets[assetID].BigInt(), amt.BigInt())
+		switch remaining.Sign() {
+		case -1:
+			return Value{}, false
+		case 0:
+			delete(assets, assetID)
+		default:
+			assets[assetID] = num.Int(*remaining)
+		}
+	}
+	for _, amt := range assets {
+		if amt.BigInt().Sign() < 0 {
+			return Value{}, false
+		}
+	}
+
+	result := Value{Coins: num.Int(*coins)}
+	if len(assets) > 0 {
+		result.Assets = assets
+	}
+	return result, true
+}
+
+// Add returns v + other, per asset; see the package level Add
+func (v Value) Add(other Value) Value {
+	return Add(v, other)
+}
+
+// Subtract returns v - other, per asset; amounts may become negative, use IsPositive or
+// TrySubtract when the result must be spendable
+func (v Value) Subtract(other Value) Value {
+	return Subtract(v, other)
+}
+
+// Equal returns true if both values hold the same amounts; see Equals
+func (v Value) Equal(other Value) bool {
+	return Equals(v, other)
+}
+
+// IsPositive returns true if no amount held by the value is negative and at least one,
+// coins or asset, is greater than zero
+func (v Value) IsPositive() bool {
+	positive := v.Coins.BigInt().Sign() > 0
+	if v.Coins.BigInt().Sign() < 0 {
+		return false
+	}
+	for _, amt := range v.Assets {
+		switch amt.BigInt().Sign() {
+		case -1:
+			return false
+		case 1:
+			positive = true
+		}
+	}
+	return positive
+}
+
+// String renders the value for logging e.g. 5.00 ADA + 3 <policy>.<name>; ada is shown in
+// whole units and assets are listed by asset id
+func (v Value) String() string {
+	ada := new(big.Rat).SetFrac(v.Coins.BigInt(), big.NewInt(1000000)).FloatString(6)
+	for strings.HasSuffix(ada, "0") && len(ada)-strings.Index(ada, ".") > 3 {
+		ada = ada[:len(ada)-1]
+	}
+
+	assetIDs := make([]string, 0, len(v.Assets))
+	for assetID, amt := range v.Assets {
+		if amt.BigInt().Sign() != 0 {
+			assetIDs = append(assetIDs, string(assetID))
+		}
+	}
+	sort.Strings(assetIDs)
+
+	parts := []string{ada + " ADA"}
+	for _, assetID := range assetIDs {
+		parts = append(parts, v.Assets[AssetID(assetID)].String()+" "+assetID)
+	}
+	return strings.Join(parts, " + ")
+}
+
 func Add(a Value, b Value) Value {
 	var result Value
 	result.Coins = a.Coins.Add(b.Coins)
@@ -586,31 +1360,100 @@ func Subtract(a Value, b Value) Value {
 	return result
 }
 func Enough(have Value, want Value) (bool, error) {
-	if have.Coins.Int64() < want.Coins.Int64() {
+	if have.Coins.BigInt().Cmp(want.Coins.BigInt()) < 0 {
 		return false, fmt.Errorf("not enough ADA to meet demand")
 	}
 	for asset, amt := range want.Assets {
-		if have.Assets[asset].Int64() < amt.Int64() {
+		if have.Assets[asset].BigInt().Cmp(amt.BigInt()) < 0 {
 			return false, fmt.Errorf("not enough %v to meet demand", asset)
 		}
 	}
 	return true, nil
 }
+// Equals returns true if both values hold the same amounts; an asset with a zero amount
+// is considered equal to an absent asset
 func Equals(left Value, right Value) bool {
-	if left.Coins.String() != right.Coins.String() {
+	if left.Coins.BigInt().Cmp(right.Coins.BigInt()) != 0 {
 		return false
 	}
 	for k, v := range left.Assets {
-		lv := v.Uint64()
-		if lv != 0 && right.Assets[k].Uint64() != lv {
+		if v.BigInt().Cmp(right.Assets[k].BigInt()) != 0 {
 			return false
 		}
 	}
 	for k, v := range right.Assets {
-		rv := v.Uint64()
-		if rv != 0 && left.Assets[k].Uint64() != rv {
+		if v.BigInt().Cmp(left.Assets[k].BigInt()) != 0 {
 			return false
 		}
 	}
 	return true
 }
+
+// RawValue is a Value whose amounts are retained as the numbers reported by ogmios.  Services
+// that pass values through without arithmetic may decode blocks into RawRollForwardBlock or
+// RawBlock, which hold their values as RawValue, to avoid converting every amount to a big.Int;
+// use Value to convert when arithmetic is required.
+type RawValue struct {
+	Coins  json.Number             `json:"coins,omitempty"  dynamodbav:"coins,omitempty"`
+	Assets map[AssetID]json.Number `json:"assets,omitempty" dynamodbav:"assets,omitempty"`
+}
+
+// Value converts the amounts of the raw value into a Value
+func (v RawValue) Value() (Value, error) {
+	var value Value
+	if v.Coins != "" {
+		coins, ok := num.New(v.Coins.String())
+		if !ok {
+			return Value{}, fmt.Errorf("failed to parse coins, %v", v.Coins)
+		}
+		value.Coins = coins
+	}
+	if len(v.Assets) > 0 {
+		value.Assets = make(map[AssetID]num.Int, len(v.Assets))
+		for assetID, amount := range v.Assets {
+			amt, ok := num.New(amount.String())
+			if !ok {
+				return Value{}, fmt.Errorf("failed to parse amount of %v, %v", assetID, amount)
+			}
+			value.Assets[assetID] = amt
+		}
+	}
+	return value, nil
+}
+
+// RawTxOut is a TxOut whose value is decoded as a RawValue; TxOut.Value is left empty
+type RawTxOut struct {
+	TxOut
+	Value RawValue `json:"value,omitempty"`
+}
+
+// RawTxBody is a TxBody whose mint and output values are decoded as RawValue; the
+// corresponding fields of the embedded TxBody are left empty
+type RawTxBody struct {
+	TxBody
+	Mint             *RawValue  `json:"mint,omitempty"`
+	Outputs          []RawTxOut `json:"outputs,omitempty"`
+	CollateralReturn *RawTxOut  `json:"collateralReturn,omitempty"`
+}
+
+// RawTx is a Tx whose values are decoded as RawValue
+type RawTx struct {
+	Tx
+	Body RawTxBody `json:"body,omitempty"`
+}
+
+// RawBlock is a Block whose values are decoded as RawValue
+type RawBlock struct {
+	Block
+	Body []RawTx `json:"body,omitempty"`
+}
+
+// RawRollForwardBlock is a RollForwardBlock whose values are decoded as RawValue
+type RawRollForwardBlock struct {
+	RollForwardBlock
+	Allegra *RawBlock `json:"allegra,omitempty"`
+	Alonzo  *RawBlock `json:"alonzo,omitempty"`
+	Babbage *RawBlock `json:"babbage,omitempty"`
+	Mary    *RawBlock `json:"mary,omitempty"`
+	Shelley *RawBlock `json:"shelley,omitempty"`
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"math/big"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -31,6 +32,7 @@ import (
 	"github.com/fxamacker/cbor/v2"
 	"github.com/nsf/jsondiff"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
 )
 
 func TestUnmarshal(t *testing.T) {
@@ -42,6 +44,13 @@ func TestUnmarshal(t *testing.T) {
 	decoder.DisallowUnknownFields()
 }
 
+func TestUnmarshal_Testdata(t *testing.T) {
+	err := filepath.Walk("testdata/RequestNext", assertStructMatchesSchema(t))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+}
+
 func assertStructMatchesSchema(t *testing.T) filepath.WalkFunc {
 	return func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
@@ -76,6 +85,13 @@ func TestDynamodbSerialize(t *testing.T) {
 	}
 }
 
+func TestDynamodbSerialize_Testdata(t *testing.T) {
+	err := filepath.Walk("testdata/RequestNext", assertDynamoDBSerialize(t))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+}
+
 func assertDynamoDBSerialize(t *testing.T) filepath.WalkFunc {
 	return func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
@@ -245,6 +261,31 @@ func TestPoint_DynamoDB(t *testing.T) {
 	})
 }
 
+func TestPoint_DynamoDBZero(t *testing.T) {
+	want := Response{
+		Result: &Result{
+			RollForward: &RollForward{
+				Block: RollForwardBlock{
+					Babbage: &Block{HeaderHash: "hash", Header: BlockHeader{Slot: 123}},
+				},
+			},
+		},
+	}
+
+	item, err := dynamodbattribute.Marshal(want)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var got Response
+	if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+}
+
 func TestPoint_JSON(t *testing.T) {
 	t.Run("string", func(t *testing.T) {
 		want := PointString("origin")
@@ -300,6 +341,143 @@ func TestPoint_JSON(t *testing.T) {
 	})
 }
 
+func TestPoint_JSONRoundTrip(t *testing.T) {
+	type Wrapper struct {
+		Point Point `json:"point"`
+	}
+
+	tests := map[string]struct {
+		Point Point
+		Want  string
+	}{
+		"null": {
+			Point: Point{},
+			Want:  `{"point":null}`,
+		},
+		"origin": {
+			Point: Origin,
+			Want:  `{"point":"origin"}`,
+		},
+		"struct": {
+			Point: PointStruct{BlockNo: 123, Hash: "hash", Slot: 456}.Point(),
+			Want:  `{"point":{"blockNo":123,"hash":"hash","slot":456}}`,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			data, err := json.Marshal(Wrapper{Point: tc.Point})
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got := string(data); got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+
+			var got Wrapper
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if !reflect.DeepEqual(got.Point, tc.Point) {
+				t.Fatalf("got %#v; want %#v", got.Point, tc.Point)
+			}
+		})
+	}
+}
+
+func TestResult_IntersectionNotFoundTip(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		data := []byte(`{"IntersectionNotFound":{"tip":{"slot":456,"hash":"hash","blockNo":123}}}`)
+		var result Result
+		if err := json.Unmarshal(data, &result); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		got, ok := result.IntersectionNotFoundTip()
+		if !ok {
+			t.Fatalf("got false; want true")
+		}
+		want := &PointStruct{BlockNo: 123, Hash: "hash", Slot: 456}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %#v; want %#v", got, want)
+		}
+	})
+
+	t.Run("origin", func(t *testing.T) {
+		result := Result{IntersectionNotFound: &IntersectionNotFound{Tip: Origin}}
+		if _, ok := result.IntersectionNotFoundTip(); ok {
+			t.Fatalf("got true; want false")
+		}
+	})
+
+	t.Run("found", func(t *testing.T) {
+		result := Result{IntersectionFound: &IntersectionFound{Point: Origin, Tip: Origin}}
+		if _, ok := result.IntersectionNotFoundTip(); ok {
+			t.Fatalf("got true; want false")
+		}
+	})
+}
+
+func TestResult_IntersectionFoundByron(t *testing.T) {
+	// last byron block on mainnet; v5 reports byron points with the same shape as later eras
+	data := []byte(`{"IntersectionFound":{"point":{"slot":4492799,"hash":"f8084c61b6a238acec985b59310b6ecec49c0ab8352249afd7268da5cff2a457"},"tip":{"slot":4492800,"hash":"aa83acbf5904c0edfe4d79b3689d3d00fcfc553cf360fd2229b98d464c28e9de","blockNo":4490511}}}`)
+
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if result.IntersectionFound == nil {
+		t.Fatalf("got nil; want IntersectionFound")
+	}
+	ps, ok := result.IntersectionFound.Point.PointStruct()
+	if !ok {
+		t.Fatalf("got false; want true")
+	}
+	if got, want := ps.Slot, uint64(4492799); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var decoded Result
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := decoded.IntersectionFound.Point.String(), result.IntersectionFound.Point.String(); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestComputeTxID(t *testing.T) {
+	body, err := cbor.Marshal(map[int]interface{}{2: 170000})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	tx, err := cbor.Marshal([]interface{}{cbor.RawMessage(body), map[int]interface{}{}, true, nil})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	got, err := ComputeTxID(hex.EncodeToString(tx))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	hash := blake2b.Sum256(body)
+	if want := TxID(hex.EncodeToString(hash[:])); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if _, err := ComputeTxID("zz"); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+	if _, err := ComputeTxID("80"); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
 func TestTxID_Index(t *testing.T) {
 	if got, want := TxID("a#3").Index(), 3; got != want {
 		t.Fatalf("got %v; want %v", got, want)
@@ -345,6 +523,42 @@ func TestPoints_Sort(t *testing.T) {
 	}
 }
 
+func TestPoints_Dedup(t *testing.T) {
+	var (
+		a = PointStruct{BlockNo: 1, Hash: "a", Slot: 10}.Point()
+		b = PointStruct{BlockNo: 2, Hash: "b", Slot: 20}.Point()
+		c = PointStruct{BlockNo: 2, Hash: "c", Slot: 20}.Point() // fork of b
+	)
+	tests := map[string]struct {
+		Input Points
+		Want  Points
+	}{
+		"nil": {
+			Input: nil,
+			Want:  Points{},
+		},
+		"unique": {
+			Input: Points{b, a, Origin},
+			Want:  Points{b, a, Origin},
+		},
+		"duplicates": {
+			Input: Points{a, b, a, Origin, b, Origin},
+			Want:  Points{a, b, Origin},
+		},
+		"same slot": {
+			Input: Points{b, c},
+			Want:  Points{b, c},
+		},
+	}
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			if got := tc.Input.Dedup(); !reflect.DeepEqual(got, tc.Want) {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+		})
+	}
+}
+
 func TestVasil(t *testing.T) {
 	data := `{
   "type": "jsonwsp/response",
@@ -545,4 +759,1469 @@ func TestValue_Equals(t *testing.T) {
 		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(10)}},
 		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(15)}},
 	))
+	assert.True(t, Equals(
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Uint64(0)}},
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10)}},
+	))
+	assert.False(t, Equals(
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Int64(-10)}},
+		Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Int64(10)}},
+	))
+}
+
+func TestTx_CollateralBalance(t *testing.T) {
+	t.Run("absent", func(t *testing.T) {
+		provided, returned, total := Tx{}.CollateralBalance()
+		assert.True(t, Equals(provided, Value{}))
+		assert.True(t, Equals(returned, Value{}))
+		assert.True(t, Equals(total, Value{}))
+	})
+
+	t.Run("total only", func(t *testing.T) {
+		tc := int64(5000000)
+		provided, returned, total := Tx{Body: TxBody{TotalCollateral: &tc}}.CollateralBalance()
+		assert.True(t, Equals(provided, Value{Coins: num.Int64(5000000)}))
+		assert.True(t, Equals(returned, Value{}))
+		assert.True(t, Equals(total, Value{Coins: num.Int64(5000000)}))
+	})
+
+	t.Run("total and return", func(t *testing.T) {
+		tc := int64(5000000)
+		tx := Tx{
+			Body: TxBody{
+				TotalCollateral: &tc,
+				CollateralReturn: &TxOut{
+					Value: Value{Coins: num.Int64(3000000), Assets: map[AssetID]num.Int{"A": num.Int64(10)}},
+				},
+			},
+		}
+		provided, returned, total := tx.CollateralBalance()
+		assert.True(t, Equals(provided, Value{Coins: num.Int64(8000000), Assets: map[AssetID]num.Int{"A": num.Int64(10)}}))
+		assert.True(t, Equals(returned, Value{Coins: num.Int64(3000000), Assets: map[AssetID]num.Int{"A": num.Int64(10)}}))
+		assert.True(t, Equals(total, Value{Coins: num.Int64(5000000)}))
+	})
+}
+
+func TestTx_Summary(t *testing.T) {
+	const data = `{
+		"id": "abc",
+		"body": {
+			"inputs": [{"txId":"a","index":0},{"txId":"b","index":1}],
+			"collaterals": [{"txId":"c","index":0}],
+			"outputs": [
+				{"address":"addr1","value":{"coins":2000000,"assets":{"p1.6e6674":1}}},
+				{"address":"addr2","value":{"coins":3000000}}
+			],
+			"collateralReturn": {"address":"addr3","value":{"coins":4000000}},
+			"fee": 170000,
+			"mint": {"coins":0,"assets":{"p1.6e6674":1,"p1.6f7468":-3,"p2.6e6674":5}},
+			"certificates": [{"stakeKeyRegistration":"k"},{"stakeDelegation":{"delegator":"k","delegatee":"pool1"}},{"stakeKeyRegistration":"j"}]
+		},
+		"metadata": {"hash":"h","body":{"blob":{"674":{"string":"msg"}}}}
+	}`
+
+	var tx Tx
+	if err := json.Unmarshal([]byte(data), &tx); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		got := tx.Summary()
+		if got.ID != "abc" || got.Inputs != 2 || got.Outputs != 2 || got.PhaseTwoFailure || !got.HasMetadata {
+			t.Fatalf("got %#v; want 2 inputs, 2 outputs, with metadata", got)
+		}
+		if got, want := got.Fee.Int64(), int64(170000); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := got.Output.Coins.Int64(), int64(5000000); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		want := map[AssetID]int64{"p1.6e6674": 1, "p1.6f7468": -3, "p2.6e6674": 5}
+		if got, want := len(got.NetMint), len(want); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		for assetID, amount := range want {
+			if got := got.NetMint[assetID].Int64(); got != amount {
+				t.Fatalf("got %v; want %v for %v", got, amount, assetID)
+			}
+		}
+		if got, want := got.CertificateTypes, []string{CertificateStakeKeyRegistration, CertificateStakeDelegation}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+
+	t.Run("phase-2 failure", func(t *testing.T) {
+		failed := tx
+		failed.InputSource = "collaterals"
+		got := failed.Summary()
+		if got.Inputs != 1 || got.Outputs != 1 || !got.PhaseTwoFailure || got.NetMint != nil {
+			t.Fatalf("got %#v; want 1 collateral input, 1 collateral return, no mint", got)
+		}
+		if got, want := got.Output.Coins.Int64(), int64(4000000); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	})
+}
+
+func TestTx_AllReferencedTxIns(t *testing.T) {
+	var (
+		a = TxIn{TxHash: "a", Index: 0}
+		b = TxIn{TxHash: "b", Index: 1}
+		c = TxIn{TxHash: "c", Index: 2}
+	)
+	tx := Tx{
+		Body: TxBody{
+			Inputs:      []TxIn{a, b},
+			References:  []TxIn{c},
+			Collaterals: []TxIn{a},
+		},
+	}
+
+	got := tx.AllReferencedTxIns()
+	want := []TxIn{c, a, b}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+}
+
+func TestTx_ResolvedInputs(t *testing.T) {
+	var (
+		a = TxIn{TxHash: "a", Index: 0}
+		c = TxIn{TxHash: "c", Index: 2}
+	)
+	tx := Tx{
+		Body: TxBody{
+			Inputs:      []TxIn{a},
+			References:  []TxIn{c},
+			Collaterals: []TxIn{a},
+		},
+	}
+	txOut := TxOut{Address: "addr", Value: Value{Coins: num.Int64(1)}}
+
+	got := tx.ResolvedInputs(map[TxID]TxOut{a.TxID(): txOut})
+	want := []ResolvedInput{
+		{TxIn: a, TxOut: &txOut, Kind: InputSpent},
+		{TxIn: c, Kind: InputReference},
+		{TxIn: a, TxOut: &txOut, Kind: InputCollateral},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+	if got, want := got[1].Kind.String(), "reference"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestTx_MissingDatums(t *testing.T) {
+	var (
+		a = TxIn{TxHash: "a", Index: 0}
+		b = TxIn{TxHash: "b", Index: 1}
+	)
+	tx := Tx{
+		Body: TxBody{
+			Inputs: []TxIn{a, b},
+			Outputs: TxOuts{
+				{Address: "addr", DatumHash: "h1"},
+				{Address: "addr", DatumHash: "h2"},
+				{Address: "addr"},
+				{Address: "addr", DatumHash: "h1"},
+			},
+			CollateralReturn: &TxOut{Address: "addr", DatumHash: "h3"},
+		},
+		Witness: Witness{
+			Datums: Datums{"h2": "d87980", "h4": "d87a80"},
+		},
+	}
+
+	got := tx.MissingDatums()
+	want := []string{"h1", "h3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	utxos := map[TxID]TxOut{
+		a.TxID(): {Address: "script", DatumHash: "h4"},
+		b.TxID(): {Address: "script", DatumHash: "h5"},
+	}
+	got = tx.MissingInputDatums(utxos)
+	want = []string{"h5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestResponse_HasReflection(t *testing.T) {
+	tests := map[string]struct {
+		Data       string
+		Want       bool
+		Reflection json.RawMessage
+	}{
+		"absent": {
+			Data:       `{"type":"jsonwsp/response"}`,
+			Want:       false,
+			Reflection: nil,
+		},
+		"null": {
+			Data:       `{"type":"jsonwsp/response","reflection":null}`,
+			Want:       false,
+			Reflection: json.RawMessage(`null`),
+		},
+		"id": {
+			Data:       `{"type":"jsonwsp/response","reflection":{"id":1}}`,
+			Want:       true,
+			Reflection: json.RawMessage(`{"id":1}`),
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var response Response
+			if err := json.Unmarshal([]byte(tc.Data), &response); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got := response.HasReflection(); got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+			if got := response.Reflection; !reflect.DeepEqual(got, tc.Reflection) {
+				t.Fatalf("got %#v; want %#v", got, tc.Reflection)
+			}
+		})
+	}
+}
+
+func TestValue_DynamoDB(t *testing.T) {
+	big, _ := num.New("18446744073709551616")
+	tests := map[string]Value{
+		"zero":        {},
+		"zero coins":  {Coins: num.Uint64(0)},
+		"zero asset":  {Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(0)}},
+		"no assets":   {Coins: num.Uint64(1), Assets: map[AssetID]num.Int{}},
+		"assets":      {Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": num.Uint64(10), "B": num.Int64(-5)}},
+		"large asset": {Coins: num.Uint64(1), Assets: map[AssetID]num.Int{"A": big}},
+	}
+
+	for label, want := range tests {
+		t.Run(label, func(t *testing.T) {
+			item, err := dynamodbattribute.Marshal(want)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+
+			var got Value
+			if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if !Equals(got, want) {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+
+			// zero amounts are preserved rather than dropped
+			w, err := json.Marshal(want)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			g, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := string(g), string(w); got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBlock_EconomicSummary(t *testing.T) {
+	block := Block{
+		Body: []Tx{
+			{
+				Body: TxBody{
+					Fee:         num.Int64(200000),
+					Mint:        &Value{Assets: map[AssetID]num.Int{"A": num.Int64(10), "B": num.Int64(-3)}},
+					Withdrawals: map[string]int64{"stake1": 1000},
+				},
+			},
+			{
+				Body: TxBody{
+					Fee:         num.Int64(300000),
+					Mint:        &Value{Assets: map[AssetID]num.Int{"A": num.Int64(5)}},
+					Withdrawals: map[string]int64{"stake2": 500},
+				},
+			},
+			{
+				Body: TxBody{
+					Fee: num.Int64(100000),
+				},
+			},
+		},
+	}
+
+	got := block.EconomicSummary()
+	assert.Equal(t, "600000", got.Fees.String())
+	assert.Equal(t, "1500", got.Withdrawals.String())
+	assert.True(t, Equals(got.Minted, Value{Assets: map[AssetID]num.Int{"A": num.Int64(15)}}))
+	assert.True(t, Equals(got.Burned, Value{Assets: map[AssetID]num.Int{"B": num.Int64(3)}}))
+}
+
+func TestResponse_UnknownMethod(t *testing.T) {
+	data := []byte(`{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"RequestNextV2","result":{"SomethingNew":{"foo":"bar"}},"reflection":{"id":1}}`)
+
+	var response Response
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := response.MethodName, "RequestNextV2"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if response.Result == nil {
+		t.Fatalf("got nil; want result")
+	}
+	if got, want := *response.Result, (Result{}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+}
+
+func TestTx_RequiredSigners(t *testing.T) {
+	var (
+		spent      = TxIn{TxHash: "a", Index: 0}
+		referenced = TxIn{TxHash: "b", Index: 0}
+	)
+	tx := Tx{
+		Body: TxBody{
+			Inputs:                  []TxIn{spent},
+			References:              []TxIn{referenced},
+			RequiredExtraSignatures: []string{"extra"},
+		},
+		Witness: Witness{
+			Scripts: json.RawMessage(`{"h1":{"native":{"any":["native1",{"all":["native2","extra"]}]}}}`),
+		},
+	}
+
+	t.Run("unresolved", func(t *testing.T) {
+		got := tx.RequiredSigners()
+		want := []string{"extra", "native1", "native2"}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %#v; want %#v", got, want)
+		}
+	})
+
+	t.Run("resolved", func(t *testing.T) {
+		utxos := map[TxID]TxOut{
+			spent.TxID():      {Address: testAddress},
+			referenced.TxID(): {Address: testAddress},
+		}
+		got := tx.RequiredSigners(tx.ResolvedInputs(utxos)...)
+		want := []string{"b5b7c56d4ee83e986f7a60ef04ecf142b4b33d0afa32f69a1004f2d8", "extra", "native1", "native2"}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %#v; want %#v", got, want)
+		}
+	})
+}
+
+func TestValue_DeterministicJSON(t *testing.T) {
+	value := Value{Coins: num.Uint64(1), Assets: map[AssetID]num.Int{}}
+	for i := 0; i < 50; i++ {
+		value.Assets[AssetID(fmt.Sprintf("policy.%02x", 50-i))] = num.Int64(int64(i))
+	}
+
+	want, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	for i := 0; i < 10; i++ {
+		item, err := dynamodbattribute.Marshal(value)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		var v Value
+		if err := dynamodbattribute.Unmarshal(item, &v); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		got, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("got %v; want %v", string(got), string(want))
+		}
+	}
+}
+
+func TestValue_LargeAmounts(t *testing.T) {
+	data := []byte(`{"coins":45000000000000000,"assets":{"a.01":9223372036854775808,"a.02":18446744073709551616,"a.03":340282366920938463463374607431768211456}}`)
+
+	var value Value
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := value.Assets["a.02"].String(), "18446744073709551616"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := string(encoded), string(data); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	item, err := dynamodbattribute.Marshal(value)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var got Value
+	if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if !Equals(got, value) {
+		t.Fatalf("got %v; want %v", got, value)
+	}
+
+	// 2^63 + 1 exceeds int64 and must not wrap when compared
+	more, _ := num.New("9223372036854775809")
+	ok, err := Enough(value, Value{Assets: map[AssetID]num.Int{"a.01": more}})
+	if ok || err == nil {
+		t.Fatalf("got %v, %v; want false, err", ok, err)
+	}
+	ok, err = Enough(value, Value{Assets: map[AssetID]num.Int{"a.02": more}})
+	if !ok || err != nil {
+		t.Fatalf("got %v, %v; want true, nil", ok, err)
+	}
+}
+
+func TestValue_MarshalJSONIntegers(t *testing.T) {
+	tests := map[string]string{
+		"beyond int64": `{"coins":9223372036854775808}`,
+		"negative":     `{"coins":0,"assets":{"a.01":-9223372036854775809}}`,
+		"large":        `{"coins":100000000000000000000000,"assets":{"a.01":1000000000000000000000}}`,
+	}
+
+	for label, data := range tests {
+		t.Run(label, func(t *testing.T) {
+			var value Value
+			if err := json.Unmarshal([]byte(data), &value); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := string(encoded), data; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+
+			// values nested within a tx out are re-serialized identically
+			encoded, err = json.Marshal(TxOut{Address: "addr", Value: value})
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := string(encoded), `{"address":"addr","value":`+data+`}`; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestValue_Predicates(t *testing.T) {
+	const policyID = "00000000000000000000000000000000000000000000000000000000"
+	tests := map[string]struct {
+		Value      Value
+		ADAOnly    bool
+		HasToken   bool
+		HasNoNamed bool
+	}{
+		"ada only": {
+			Value:   Value{Coins: num.Int64(2000000)},
+			ADAOnly: true,
+		},
+		"zero amount": {
+			Value:   Value{Coins: num.Int64(2000000), Assets: map[AssetID]num.Int{policyID + ".01": num.Int64(0)}},
+			ADAOnly: true,
+		},
+		"named asset": {
+			Value:    Value{Coins: num.Int64(2000000), Assets: map[AssetID]num.Int{policyID + ".01": num.Int64(1)}},
+			HasToken: true,
+		},
+		"unnamed asset": {
+			Value:      Value{Coins: num.Int64(2000000), Assets: map[AssetID]num.Int{policyID: num.Int64(1)}},
+			HasNoNamed: true,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			if got, want := tc.Value.IsADAOnly(), tc.ADAOnly; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			if got, want := tc.Value.HasAsset(policyID, "01"), tc.HasToken; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			if got, want := tc.Value.HasAsset(policyID, ""), tc.HasNoNamed; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTx_Minimal(t *testing.T) {
+	// transactions such as byron-era ones may carry neither raw nor witness data
+	tests := map[string]string{
+		"id only":      `{"id":"a8f1ad64baa1546faf1a6da8adc0cfdc9fbaf2b18ceef3781f96f2a2a4d1b254"}`,
+		"empty body":   `{"id":"a8f1ad64baa1546faf1a6da8adc0cfdc9fbaf2b18ceef3781f96f2a2a4d1b254","body":{"inputs":[],"outputs":[]}}`,
+		"no raw field": `{"id":"a8f1ad64baa1546faf1a6da8adc0cfdc9fbaf2b18ceef3781f96f2a2a4d1b254","body":{"fee":0},"witness":{}}`,
+	}
+
+	for label, data := range tests {
+		t.Run(label, func(t *testing.T) {
+			var tx Tx
+			if err := json.Unmarshal([]byte(data), &tx); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := tx.ID, "a8f1ad64baa1546faf1a6da8adc0cfdc9fbaf2b18ceef3781f96f2a2a4d1b254"; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			if got, want := len(tx.Body.Inputs), 0; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBlock_IssuerCounter(t *testing.T) {
+	tests := map[string]struct {
+		Data string
+		Want uint64
+	}{
+		"opCert": {
+			Data: `{"header":{"opCert":{"count":4,"hotVk":"3f2e3d4c5b6a7988","kesPeriod":420}}}`,
+			Want: 4,
+		},
+		"no opCert": {
+			Data: `{"header":{"blockHeight":1}}`,
+			Want: 0,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var block Block
+			if err := json.Unmarshal([]byte(tc.Data), &block); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := block.IssuerCounter(), tc.Want; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+
+			item, err := dynamodbattribute.Marshal(block)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			var decoded Block
+			if err := dynamodbattribute.Unmarshal(item, &decoded); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := decoded.IssuerCounter(), tc.Want; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTx_DynamoDBRoundTrip(t *testing.T) {
+	tests := map[string]Tx{
+		"raw": {
+			ID:  "a8f1ad64baa1546faf1a6da8adc0cfdc9fbaf2b18ceef3781f96f2a2a4d1b254",
+			Raw: "hKQAgYJYIA==",
+		},
+		"no raw": {
+			ID: "a8f1ad64baa1546faf1a6da8adc0cfdc9fbaf2b18ceef3781f96f2a2a4d1b254",
+			Body: TxBody{
+				Fee:    num.Int64(170000),
+				Inputs: []TxIn{{TxHash: "abc", Index: 1}},
+			},
+		},
+	}
+
+	for label, tx := range tests {
+		t.Run(label, func(t *testing.T) {
+			item, err := dynamodbattribute.Marshal(tx)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			var got Tx
+			if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+
+			want, _ := json.Marshal(tx)
+			encoded, _ := json.Marshal(got)
+			if string(encoded) != string(want) {
+				t.Fatalf("got %v; want %v", string(encoded), string(want))
+			}
+		})
+	}
+}
+
+func TestValue_String(t *testing.T) {
+	tests := map[string]struct {
+		Value Value
+		Want  string
+	}{
+		"zero": {
+			Want: "0.00 ADA",
+		},
+		"whole ada": {
+			Value: Value{Coins: num.Int64(5000000)},
+			Want:  "5.00 ADA",
+		},
+		"lovelace": {
+			Value: Value{Coins: num.Int64(1234567)},
+			Want:  "1.234567 ADA",
+		},
+		"assets": {
+			Value: Value{
+				Coins: num.Int64(5000000),
+				Assets: map[AssetID]num.Int{
+					"b.02": num.Int64(7),
+					"a.01": num.Int64(3),
+					"c.03": num.Int64(0),
+				},
+			},
+			Want: "5.00 ADA + 3 a.01 + 7 b.02",
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			if got, want := tc.Value.String(), tc.Want; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestValue_AssetsOnly(t *testing.T) {
+	data := []byte(`{"assets":{"a.01":3}}`)
+
+	var value Value
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := value.Coin().Sign(), 0; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := value.Assets["a.01"].Int64(), int64(3); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	item, err := dynamodbattribute.Marshal(value)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var decoded Value
+	if err := dynamodbattribute.Unmarshal(item, &decoded); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := decoded.Coin().Sign(), 0; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	// mutating the returned coin must not affect the value
+	value = Value{Coins: num.Int64(5)}
+	value.Coin().SetInt64(10)
+	if got, want := value.Coins.Int64(), int64(5); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestTx_ConservesValue(t *testing.T) {
+	var (
+		a     = TxIn{TxHash: "a", Index: 0}
+		b     = TxIn{TxHash: "b", Index: 1}
+		c     = TxIn{TxHash: "c", Index: 2}
+		total = int64(300000)
+		utxos = map[TxID]TxOut{
+			a.TxID(): {Value: Value{Coins: num.Int64(10000000)}},
+			b.TxID(): {Value: Value{Coins: num.Int64(2000000), Assets: map[AssetID]num.Int{"p.01": num.Int64(5)}}},
+			c.TxID(): {Value: Value{Coins: num.Int64(5000000)}},
+		}
+	)
+
+	tests := map[string]struct {
+		Tx        Tx
+		Want      bool
+		Imbalance Value
+	}{
+		"balanced": {
+			Tx: Tx{Body: TxBody{
+				Inputs:      []TxIn{a, b},
+				Withdrawals: map[string]int64{"stake": 1000000},
+				Mint:        &Value{Assets: map[AssetID]num.Int{"p.01": num.Int64(-2), "p.02": num.Int64(1)}},
+				Outputs: TxOuts{
+					{Value: Value{Coins: num.Int64(12800000), Assets: map[AssetID]num.Int{"p.01": num.Int64(3), "p.02": num.Int64(1)}}},
+				},
+				Fee: num.Int64(200000),
+			}},
+			Want: true,
+		},
+		"imbalanced": {
+			Tx: Tx{Body: TxBody{
+				Inputs:  []TxIn{a},
+				Outputs: TxOuts{{Value: Value{Coins: num.Int64(7000000)}}},
+				Fee:     num.Int64(200000),
+			}},
+			Imbalance: Value{Coins: num.Int64(2800000)},
+		},
+		"collateral": {
+			Tx: Tx{
+				InputSource: "collaterals",
+				Body: TxBody{
+					Inputs:           []TxIn{a},
+					Collaterals:      []TxIn{c},
+					CollateralReturn: &TxOut{Value: Value{Coins: num.Int64(4700000)}},
+					TotalCollateral:  &total,
+					Outputs:          TxOuts{{Value: Value{Coins: num.Int64(1)}}},
+				},
+			},
+			Want: true,
+		},
+		"collateral mismatch": {
+			Tx: Tx{
+				InputSource: "collaterals",
+				Body: TxBody{
+					Collaterals:      []TxIn{c},
+					CollateralReturn: &TxOut{Value: Value{Coins: num.Int64(4000000)}},
+					TotalCollateral:  &total,
+				},
+			},
+			Imbalance: Value{Coins: num.Int64(700000)},
+		},
+		"collateral without total": {
+			Tx: Tx{
+				InputSource: "collaterals",
+				Body:        TxBody{Collaterals: []TxIn{c}},
+			},
+			Want: true,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			got, imbalance := tc.Tx.ConservesValue(utxos)
+			if got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+			if !Equals(imbalance, tc.Imbalance) {
+				t.Fatalf("got %v; want %v", imbalance, tc.Imbalance)
+			}
+		})
+	}
+}
+
+// testBlock returns the babbage block from testdata with its transactions repeated n times
+func testBlock(t testing.TB, n int) []byte {
+	data, err := os.ReadFile("testdata/RequestNext/babbage.json")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	var response struct {
+		Result struct {
+			RollForward struct {
+				Block struct {
+					Babbage map[string]json.RawMessage
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	block := response.Result.RollForward.Block.Babbage
+
+	var txs []json.RawMessage
+	if err := json.Unmarshal(block["body"], &txs); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var body []json.RawMessage
+	for i := 0; i < n; i++ {
+		body = append(body, txs...)
+	}
+	if block["body"], err = json.Marshal(body); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	data, err = json.Marshal(block)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return data
+}
+
+func TestDecodeBlockHeader(t *testing.T) {
+	data := testBlock(t, 1)
+
+	var want Block
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	want.Body = nil
+
+	got, err := DecodeBlockHeader(data)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Fatalf("got %#v; want %#v", *got, want)
+	}
+	if got.HeaderHash == "" || got.Header.Slot == 0 {
+		t.Fatalf("got empty header; want populated header")
+	}
+
+	if _, err := DecodeBlockHeader([]byte(`{"header":`)); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func BenchmarkDecodeBlockHeader(b *testing.B) {
+	data := testBlock(b, 250)
+
+	b.Run("DecodeBlockHeader", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			if _, err := DecodeBlockHeader(data); err != nil {
+				b.Fatalf("got %v; want nil", err)
+			}
+		}
+	})
+
+	b.Run("json.Unmarshal", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			var block Block
+			if err := json.Unmarshal(data, &block); err != nil {
+				b.Fatalf("got %v; want nil", err)
+			}
+		}
+	})
+}
+
+func TestBlock_Equal(t *testing.T) {
+	const block = `{"headerHash":"abc","header":{"blockHeight":1,"opCert":{"count":4,"kesPeriod":420}},"body":[{"id":"tx","body":{"fee":170000,"outputs":[{"address":"addr","value":{"coins":2000000,"assets":{"a.b":1,"c.d":2}}}]},"witness":{"datums":{"h1":"d8799f","h2":"d87a80"},"scripts":{"s1":{"native":{"any":[]}},"s2":{"plutus:v2":"4d01"}}}}]}`
+
+	tests := map[string]struct {
+		Other string
+		Want  bool
+	}{
+		"identical": {
+			Other: block,
+			Want:  true,
+		},
+		"reordered maps": {
+			Other: `{"headerHash":"abc","header":{"opCert":{"kesPeriod":420,"count":4},"blockHeight":1},"body":[{"id":"tx","body":{"fee":170000,"outputs":[{"address":"addr","value":{"coins":2000000,"assets":{"c.d":2,"a.b":1}}}]},"witness":{"datums":{"h2":"d87a80","h1":"d8799f"},"scripts":{"s2":{"plutus:v2":"4d01"},"s1":{"native":{"any":[]}}}}}]}`,
+			Want:  true,
+		},
+		"different asset amount": {
+			Other: `{"headerHash":"abc","header":{"blockHeight":1,"opCert":{"count":4,"kesPeriod":420}},"body":[{"id":"tx","body":{"fee":170000,"outputs":[{"address":"addr","value":{"coins":2000000,"assets":{"a.b":1,"c.d":3}}}]},"witness":{"datums":{"h1":"d8799f","h2":"d87a80"},"scripts":{"s1":{"native":{"any":[]}},"s2":{"plutus:v2":"4d01"}}}}]}`,
+			Want:  false,
+		},
+		"different script": {
+			Other: `{"headerHash":"abc","header":{"blockHeight":1,"opCert":{"count":4,"kesPeriod":420}},"body":[{"id":"tx","body":{"fee":170000,"outputs":[{"address":"addr","value":{"coins":2000000,"assets":{"a.b":1,"c.d":2}}}]},"witness":{"datums":{"h1":"d8799f","h2":"d87a80"},"scripts":{"s1":{"native":{"any":[]}},"s2":{"plutus:v2":"4d02"}}}}]}`,
+			Want:  false,
+		},
+		"missing tx": {
+			Other: `{"headerHash":"abc","header":{"blockHeight":1,"opCert":{"count":4,"kesPeriod":420}}}`,
+			Want:  false,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var a, b Block
+			if err := json.Unmarshal([]byte(block), &a); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if err := json.Unmarshal([]byte(tc.Other), &b); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := a.Equal(b), tc.Want; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			if got, want := b.Equal(a), tc.Want; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+
+	t.Run("dynamodb round trip", func(t *testing.T) {
+		var want Block
+		if err := json.Unmarshal([]byte(block), &want); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		item, err := dynamodbattribute.Marshal(want)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		var got Block
+		if err := dynamodbattribute.Unmarshal(item, &got); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("got %#v; want %#v", got, want)
+		}
+	})
+}
+
+func TestBlockTxIDs(t *testing.T) {
+	data := testBlock(t, 2)
+
+	var block Block
+	if err := json.Unmarshal(data, &block); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var want []string
+	for _, tx := range block.Body {
+		want = append(want, tx.ID)
+	}
+
+	got, err := BlockTxIDs(data)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if len(got) == 0 || !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if got, err := BlockTxIDs([]byte(`{"header":{"slot":1}}`)); err != nil || len(got) != 0 {
+		t.Fatalf("got %v, %v; want no ids", got, err)
+	}
+	if _, err := BlockTxIDs([]byte(`{"body":[{"fee":1}]}`)); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func BenchmarkBlockTxIDs(b *testing.B) {
+	data := testBlock(b, 250)
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := BlockTxIDs(data); err != nil {
+			b.Fatalf("got %v; want nil", err)
+		}
+	}
+}
+
+func TestBlockHeader_HasLeaderValue(t *testing.T) {
+	tests := map[string]struct {
+		Data string
+		Want bool
+	}{
+		"alonzo": {
+			Data: `{"header":{"slot":1,"nonce":{"output":"AAE=","proof":"AAI="},"leaderValue":{"output":"AAM=","proof":"AAQ="}}}`,
+			Want: true,
+		},
+		"babbage": {
+			Data: `{"header":{"slot":1,"vrfInput":{"output":"AAE=","proof":"AAI="}}}`,
+		},
+		"nonce only": {
+			Data: `{"header":{"slot":1,"nonce":{"output":"AAE=","proof":"AAI="}}}`,
+		},
+		"null": {
+			Data: `{"header":{"slot":1,"nonce":null,"leaderValue":null}}`,
+		},
+		"byron": {
+			Data: `{"header":{"blockHeight":1,"genesisKey":"abc","prevHash":"def"}}`,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var block Block
+			if err := json.Unmarshal([]byte(tc.Data), &block); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := block.Header.HasLeaderValue(), tc.Want; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+
+			item, err := dynamodbattribute.Marshal(block)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			var decoded Block
+			if err := dynamodbattribute.Unmarshal(item, &decoded); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := decoded.Header.HasLeaderValue(), tc.Want; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRollBackward_TipWithoutHeight(t *testing.T) {
+	tests := map[string]struct {
+		Data    string
+		TipSlot uint64
+		TipHash string
+		Origin  bool
+	}{
+		"full tip": {
+			Data:    `{"result":{"RollBackward":{"point":{"slot":10,"hash":"a"},"tip":{"slot":20,"hash":"b","blockNo":3}}}}`,
+			TipSlot: 20,
+			TipHash: "b",
+		},
+		"tip without height": {
+			Data:    `{"result":{"RollBackward":{"point":{"slot":10,"hash":"a"},"tip":{"slot":20,"hash":"b"}}}}`,
+			TipSlot: 20,
+			TipHash: "b",
+		},
+		"origin tip": {
+			Data:   `{"result":{"RollBackward":{"point":"origin","tip":"origin"}}}`,
+			Origin: true,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var response Response
+			if err := json.Unmarshal([]byte(tc.Data), &response); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			rollback := response.Result.RollBackward
+			if rollback == nil {
+				t.Fatalf("got nil; want RollBackward")
+			}
+
+			check := func(t *testing.T, tip Point) {
+				if tc.Origin {
+					if got, want := tip.String(), "origin"; got != want {
+						t.Fatalf("got %v; want %v", got, want)
+					}
+					return
+				}
+				ps, ok := tip.PointStruct()
+				if !ok {
+					t.Fatalf("got false; want true")
+				}
+				if ps.Slot != tc.TipSlot || ps.Hash != tc.TipHash {
+					t.Fatalf("got %v; want slot %v, hash %v", ps, tc.TipSlot, tc.TipHash)
+				}
+			}
+			check(t, rollback.Tip)
+
+			item, err := dynamodbattribute.Marshal(rollback)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			var decoded RollBackward
+			if err := dynamodbattribute.Unmarshal(item, &decoded); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			check(t, decoded.Tip)
+		})
+	}
+}
+
+func TestWitness_BootstrapWitnesses(t *testing.T) {
+	data := `{"bootstrap":[{"key":"0a0b","signature":"AQI=","chainCode":"a0b1c2","addressAttributes":"oQFYHlgcmBbkalcXDw=="},{"key":"0c0d","signature":"AwQ=","chainCode":"d3e4f5","addressAttributes":null}]}`
+
+	var witness Witness
+	if err := json.Unmarshal([]byte(data), &witness); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	witnesses, err := witness.BootstrapWitnesses()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(witnesses), 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	chainCode, err := witnesses[0].ChainCodeBytes()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := hex.EncodeToString(chainCode), "a0b1c2"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	attributes, err := witnesses[0].AddressAttributesBytes()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := hex.EncodeToString(attributes), "a101581e581c9816e46a57170f"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	// witnesses of addresses without attributes
+	attributes, err = witnesses[1].AddressAttributesBytes()
+	if err != nil || len(attributes) != 0 {
+		t.Fatalf("got %v, %v; want empty, nil", attributes, err)
+	}
+
+	if _, err := (BootstrapWitness{ChainCode: "not!encoded"}).ChainCodeBytes(); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func TestValue_TrySubtract(t *testing.T) {
+	tests := map[string]struct {
+		Have string
+		Sub  string
+		Want string
+		OK   bool
+	}{
+		"coins": {
+			Have: `{"coins":10}`,
+			Sub:  `{"coins":4}`,
+			Want: `{"coins":6}`,
+			OK:   true,
+		},
+		"exact": {
+			Have: `{"coins":10,"assets":{"a.01":5}}`,
+			Sub:  `{"coins":10,"assets":{"a.01":5}}`,
+			Want: `{"coins":0}`,
+			OK:   true,
+		},
+		"remainder": {
+			Have: `{"coins":10,"assets":{"a.01":5,"b.02":18446744073709551616}}`,
+			Sub:  `{"coins":1,"assets":{"a.01":5,"b.02":1}}`,
+			Want: `{"coins":9,"assets":{"b.02":18446744073709551615}}`,
+			OK:   true,
+		},
+		"not enough coins": {
+			Have: `{"coins":10,"assets":{"a.01":5}}`,
+			Sub:  `{"coins":11}`,
+		},
+		"not enough asset": {
+			Have: `{"coins":10,"assets":{"a.01":5}}`,
+			Sub:  `{"coins":1,"assets":{"a.01":6}}`,
+		},
+		"missing asset": {
+			Have: `{"coins":10}`,
+			Sub:  `{"assets":{"a.01":1}}`,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var have, sub Value
+			if err := json.Unmarshal([]byte(tc.Have), &have); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if err := json.Unmarshal([]byte(tc.Sub), &sub); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+
+			got, ok := have.TrySubtract(sub)
+			if ok != tc.OK {
+				t.Fatalf("got %v; want %v", ok, tc.OK)
+			}
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if string(data) != tc.Want {
+				t.Fatalf("got %v; want %v", string(data), tc.Want)
+			}
+			if ok, _ := Enough(have, sub); !ok {
+				t.Fatalf("got false; want Enough to agree")
+			}
+		})
+	}
+}
+
+func TestValue_Arithmetic(t *testing.T) {
+	const policy = "e6f7f0e5af9b5a9ef28d2b4e4b3b2e1f5f1ff4d8b2ab9e2bbd5cbc4e"
+
+	tests := map[string]struct {
+		A          string
+		B          string
+		Sum        string
+		Difference string
+		Positive   bool
+	}{
+		"coins": {
+			A:          `{"coins":2000000}`,
+			B:          `{"coins":500000}`,
+			Sum:        `{"coins":2500000}`,
+			Difference: `{"coins":1500000}`,
+			Positive:   true,
+		},
+		"multi-asset": {
+			A:          `{"coins":1689618,"assets":{"` + policy + `.4d494e":100,"` + policy + `.534e454b":7}}`,
+			B:          `{"coins":689618,"assets":{"` + policy + `.4d494e":40}}`,
+			Sum:        `{"coins":2379236,"assets":{"` + policy + `.4d494e":140,"` + policy + `.534e454b":7}}`,
+			Difference: `{"coins":1000000,"assets":{"` + policy + `.4d494e":60,"` + policy + `.534e454b":7}}`,
+			Positive:   true,
+		},
+		"negative asset": {
+			A:          `{"coins":3000000,"assets":{"` + policy + `.4d494e":1}}`,
+			B:          `{"coins":1000000,"assets":{"` + policy + `.4d494e":5}}`,
+			Sum:        `{"coins":4000000,"assets":{"` + policy + `.4d494e":6}}`,
+			Difference: `{"coins":2000000,"assets":{"` + policy + `.4d494e":-4}}`,
+		},
+		"negative coins": {
+			A:          `{"coins":1,"assets":{"` + policy + `.4d494e":5}}`,
+			B:          `{"coins":2}`,
+			Sum:        `{"coins":3,"assets":{"` + policy + `.4d494e":5}}`,
+			Difference: `{"coins":-1,"assets":{"` + policy + `.4d494e":5}}`,
+		},
+		"zero": {
+			A:          `{"coins":5,"assets":{"` + policy + `.4d494e":5}}`,
+			B:          `{"coins":5,"assets":{"` + policy + `.4d494e":5}}`,
+			Sum:        `{"coins":10,"assets":{"` + policy + `.4d494e":10}}`,
+			Difference: `{}`,
+		},
+	}
+
+	decode := func(t *testing.T, s string) Value {
+		var v Value
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		return v
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			a, b := decode(t, tc.A), decode(t, tc.B)
+
+			if got, want := a.Add(b), decode(t, tc.Sum); !got.Equal(want) {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			if got, want := a.Add(b), b.Add(a); !got.Equal(want) {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+
+			difference := a.Subtract(b)
+			if want := decode(t, tc.Difference); !difference.Equal(want) {
+				t.Fatalf("got %v; want %v", difference, want)
+			}
+			if got, want := difference.IsPositive(), tc.Positive; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			if got := difference.Add(b); !got.Equal(a) {
+				t.Fatalf("got %v; want %v", got, a)
+			}
+		})
+	}
+}
+
+func TestValue_Policies(t *testing.T) {
+	const (
+		policyA = "29d222ce763455e3d7a09a665ce554f00ac89d2e99a1a83d267170c6"
+		policyB = "e6f7f0e5af9b5a9ef28d2b4e4b3b2e1f5f1ff4d8b2ab9e2bbd5cbc4e"
+	)
+
+	var value Value
+	data := `{"coins":1500000,"assets":{"` + policyB + `.4d494e":100,"` + policyB + `":1,"` + policyA + `.534e454b":7,"` + policyA + `.00":0}}`
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	if got, want := value.Policies(), []string{policyA, policyB}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := value.AssetCount(), 3; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	want := map[string]*big.Int{"4d494e": big.NewInt(100), "": big.NewInt(1)}
+	if got := value.AssetsUnder(policyB); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got := value.AssetsUnder("unknown"); len(got) != 0 {
+		t.Fatalf("got %v; want empty", got)
+	}
+
+	if got := (Value{}).Policies(); len(got) != 0 {
+		t.Fatalf("got %v; want empty", got)
+	}
+}
+
+func TestSplitAssetID(t *testing.T) {
+	const (
+		sundae = "9a9693a9a37912a5097918f97918d15240c92ab729a0b7c4aa144d77"
+		hosky  = "a0028f350aaabe0545fdcb56b039bfb08e4bb4d8c4d7c3c7d481c235"
+	)
+
+	tests := map[string]struct {
+		ID     string
+		Policy string
+		Name   string
+	}{
+		"dotted": {
+			ID:     sundae + ".53554e444145",
+			Policy: sundae,
+			Name:   "53554e444145",
+		},
+		"concatenated": {
+			ID:     hosky + "484f534b59",
+			Policy: hosky,
+			Name:   "484f534b59",
+		},
+		"empty name": {
+			ID:     hosky,
+			Policy: hosky,
+		},
+		"ada": {
+			ID: "ada",
+		},
+		"lovelace": {
+			ID: "lovelace",
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			policy, name := SplitAssetID(tc.ID)
+			if policy != tc.Policy {
+				t.Fatalf("got %v; want %v", policy, tc.Policy)
+			}
+			if name != tc.Name {
+				t.Fatalf("got %v; want %v", name, tc.Name)
+			}
+		})
+	}
+}
+
+func TestValue_Flatten(t *testing.T) {
+	const (
+		sundae = "9a9693a9a37912a5097918f97918d15240c92ab729a0b7c4aa144d77"
+		hosky  = "a0028f350aaabe0545fdcb56b039bfb08e4bb4d8c4d7c3c7d481c235"
+	)
+
+	var value Value
+	data := `{"coins":1500000,"assets":{"` + sundae + `.53554e444145":250,"` + hosky + `.484f534b59":18446744073709551616,"` + hosky + `.00":0}}`
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	coins := value.Flatten()
+	var ids []AssetID
+	for _, coin := range coins {
+		ids = append(ids, coin.AssetID)
+	}
+	want := []AssetID{AdaAssetID, sundae + ".53554e444145", hosky + ".484f534b59"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("got %v; want %v", ids, want)
+	}
+	if got, want := coins[0].Amount.Int64(), int64(1500000); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if got, want := coins[0].PolicyID()+coins[0].AssetName(), ""; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := coins[1].PolicyID(), sundae; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := coins[1].AssetName(), "53554e444145"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if got := ValueFromCoins(coins); !got.Equal(value) {
+		t.Fatalf("got %v; want %v", got, value)
+	}
+
+	doubled := ValueFromCoins(append(coins, coins...))
+	if got, want := doubled, value.Add(value); !got.Equal(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestRawValue(t *testing.T) {
+	const data = `{"coins":123456789012345678901234567890,"assets":{"abc.6e6674":18446744073709551616}}`
+
+	var raw RawValue
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := string(encoded), data; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	got, err := raw.Value()
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var want Value
+	if err := json.Unmarshal([]byte(data), &want); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if !Equals(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	t.Run("block", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/RequestNext/babbage.json")
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		var response struct {
+			Result struct {
+				RollForward struct {
+					Block RawRollForwardBlock
+				}
+			}
+		}
+		if err := json.Unmarshal(data, &response); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		block := response.Result.RollForward.Block.Babbage
+		if block == nil || len(block.Body) == 0 {
+			t.Fatalf("got %#v; want babbage block", response.Result.RollForward.Block)
+		}
+		tx := block.Body[0]
+		if got, want := tx.ID, "8a5c7f63f4dd4ba8eb4d4b6a2ea1c5e3d4a8c64df8a0d0c1f1e3ab4d0e7a4b21"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+
+		output := tx.Body.Outputs[0]
+		if got, want := output.Value.Assets["c88bbd1848db5ea665b1fffbefba86e8dcd723b5085348e8a8d2260f.44414e41"], json.Number("6599517526229999871"); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if output.TxOut.Value.Assets != nil {
+			t.Fatalf("got %v; want embedded value left empty", output.TxOut.Value)
+		}
+		if tx.Body.Mint == nil || tx.Body.Mint.Assets["9d1cbb54faf284f5d262f591b1f9201a1858de155157dad49f3881c4"] != "1" {
+			t.Fatalf("got %v; want mint of 1", tx.Body.Mint)
+		}
+		if got, want := tx.Body.Fee.Int64(), int64(0); got == want {
+			t.Fatalf("got %v; want fee decoded by the embedded body", got)
+		}
+
+		var typed struct {
+			Result struct {
+				RollForward struct {
+					Block RollForwardBlock
+				}
+			}
+		}
+		if err := json.Unmarshal(data, &typed); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		want := typed.Result.RollForward.Block.Babbage.Body[0].Body.Outputs[0].Value
+		if got, err := output.Value.Value(); err != nil || !Equals(got, want) {
+			t.Fatalf("got %v, %v; want %v", got, err, want)
+		}
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		raw := RawValue{Coins: "1.5"}
+		if _, err := raw.Value(); err == nil {
+			t.Fatalf("got nil; want error")
+		}
+	})
 }
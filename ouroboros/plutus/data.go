@@ -0,0 +1,268 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plutus decodes plutus data, as found in datums and redeemers, without requiring
+// a plutus vm
+package plutus
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+const maxDepth = 256
+
+type constr struct {
+	Constructor uint64            `json:"constructor"`
+	Fields      []json.RawMessage `json:"fields"`
+}
+
+type mapEntry struct {
+	K json.RawMessage `json:"k"`
+	V json.RawMessage `json:"v"`
+}
+
+// DecodeToJSON converts hex encoded plutus data into its json representation; each node is
+// one of {"constructor":n,"fields":[...]}, {"map":[{"k":...,"v":...}]}, {"list":[...]},
+// {"int":n}, or {"bytes":"<hex>"}
+func DecodeToJSON(hexCBOR string) (json.RawMessage, error) {
+	data, err := hex.DecodeString(hexCBOR)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode plutus data: invalid hex: %w", err)
+	}
+
+	d := decoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode plutus data: %w", err)
+	}
+	if d.pos != len(data) {
+		return nil, fmt.Errorf("failed to decode plutus data: %v trailing bytes", len(data)-d.pos)
+	}
+	return v, nil
+}
+
+// decoder walks the cbor by hand as plutus data relies on map ordering and byte string
+// keys, neither of which survive decoding into generic go values
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+// header reads the initial byte of a data item returning its major type and argument
+func (d *decoder) header() (major byte, arg uint64, indefinite bool, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, false, fmt.Errorf("unexpected end of data")
+	}
+	b := d.data[d.pos]
+	d.pos++
+
+	major, info := b>>5, b&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), false, nil
+	case info == 31:
+		return major, 0, true, nil
+	case info > 27:
+		return 0, 0, false, fmt.Errorf("invalid additional info, %v", info)
+	}
+
+	n := 1 << (info - 24)
+	if d.pos+n > len(d.data) {
+		return 0, 0, false, fmt.Errorf("unexpected end of data")
+	}
+	buf := make([]byte, 8)
+	copy(buf[8-n:], d.data[d.pos:d.pos+n])
+	d.pos += n
+	return major, binary.BigEndian.Uint64(buf), false, nil
+}
+
+// isBreak consumes the break byte terminating an indefinite length item, if present
+func (d *decoder) isBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == 0xff {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+func (d *decoder) decode(depth int) (json.RawMessage, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("exceeded max depth, %v", maxDepth)
+	}
+
+	major, arg, indefinite, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		return json.Marshal(map[string]*big.Int{"int": new(big.Int).SetUint64(arg)})
+
+	case 1:
+		n := new(big.Int).SetUint64(arg)
+		return json.Marshal(map[string]*big.Int{"int": n.Neg(n).Sub(n, big.NewInt(1))})
+
+	case 2:
+		data, err := d.bytes(arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"bytes": hex.EncodeToString(data)})
+
+	case 4:
+		items, err := d.list(arg, indefinite, depth)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string][]json.RawMessage{"list": items})
+
+	case 5:
+		entries := []mapEntry{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.isBreak() {
+				break
+			}
+			k, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, mapEntry{K: k, V: v})
+		}
+		return json.Marshal(map[string][]mapEntry{"map": entries})
+
+	case 6:
+		return d.tag(arg, depth)
+
+	default:
+		return nil, fmt.Errorf("unexpected cbor major type, %v", major)
+	}
+}
+
+// bytes reads the content of a byte string, concatenating the chunks of indefinite strings
+func (d *decoder) bytes(n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, fmt.Errorf("unexpected end of data")
+		}
+		data := d.data[d.pos : d.pos+int(n)]
+		d.pos += int(n)
+		return data, nil
+	}
+
+	var data []byte
+	for !d.isBreak() {
+		major, n, indefinite, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		if major != 2 || indefinite {
+			return nil, fmt.Errorf("invalid byte string chunk")
+		}
+		chunk, err := d.bytes(n, false)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, chunk...)
+	}
+	return data, nil
+}
+
+func (d *decoder) list(n uint64, indefinite bool, depth int) ([]json.RawMessage, error) {
+	items := []json.RawMessage{}
+	for i := uint64(0); indefinite || i < n; i++ {
+		if indefinite && d.isBreak() {
+			break
+		}
+		item, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// tag decodes bignums and constructors; constructors 0-6 use tags 121-127, 7-127 use tags
+// 1280-1400, and any other constructor uses tag 102 with an explicit index
+func (d *decoder) tag(tag uint64, depth int) (json.RawMessage, error) {
+	switch {
+	case tag == 2 || tag == 3:
+		major, n, indefinite, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		if major != 2 {
+			return nil, fmt.Errorf("invalid bignum")
+		}
+		data, err := d.bytes(n, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		v := new(big.Int).SetBytes(data)
+		if tag == 3 {
+			v.Neg(v).Sub(v, big.NewInt(1))
+		}
+		return json.Marshal(map[string]*big.Int{"int": v})
+
+	case tag >= 121 && tag <= 127:
+		return d.constr(tag-121, depth)
+
+	case tag >= 1280 && tag <= 1400:
+		return d.constr(tag-1280+7, depth)
+
+	case tag == 102:
+		major, n, _, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		if major != 4 || n != 2 {
+			return nil, fmt.Errorf("invalid constructor")
+		}
+		major, index, _, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		if major != 0 {
+			return nil, fmt.Errorf("invalid constructor index")
+		}
+		return d.constr(index, depth)
+
+	default:
+		return nil, fmt.Errorf("unexpected cbor tag, %v", tag)
+	}
+}
+
+func (d *decoder) constr(index uint64, depth int) (json.RawMessage, error) {
+	major, n, indefinite, err := d.header()
+	if err != nil {
+		return nil, err
+	}
+	if major != 4 {
+		return nil, fmt.Errorf("invalid constructor fields")
+	}
+	fields, err := d.list(n, indefinite, depth)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(constr{Constructor: index, Fields: fields})
+}
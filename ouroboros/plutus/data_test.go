@@ -0,0 +1,105 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plutus
+
+import (
+	"testing"
+)
+
+func TestDecodeToJSON(t *testing.T) {
+	tests := map[string]struct {
+		CBOR string
+		Want string
+	}{
+		"unit": {
+			CBOR: "d87980",
+			Want: `{"constructor":0,"fields":[]}`,
+		},
+		"indefinite fields": {
+			CBOR: "d8799f4100ff",
+			Want: `{"constructor":0,"fields":[{"bytes":"00"}]}`,
+		},
+		"constructor 9": {
+			CBOR: "d9050280",
+			Want: `{"constructor":9,"fields":[]}`,
+		},
+		"general constructor": {
+			CBOR: "d8668218c880",
+			Want: `{"constructor":200,"fields":[]}`,
+		},
+		"int": {
+			CBOR: "1864",
+			Want: `{"int":100}`,
+		},
+		"negative int": {
+			CBOR: "20",
+			Want: `{"int":-1}`,
+		},
+		"bignum": {
+			CBOR: "c249010000000000000000",
+			Want: `{"int":18446744073709551616}`,
+		},
+		"negative bignum": {
+			CBOR: "c349010000000000000000",
+			Want: `{"int":-18446744073709551617}`,
+		},
+		"indefinite bytes": {
+			CBOR: "5f41014102ff",
+			Want: `{"bytes":"0102"}`,
+		},
+		"list": {
+			CBOR: "9f0102ff",
+			Want: `{"list":[{"int":1},{"int":2}]}`,
+		},
+		"map": {
+			CBOR: "a2410202410101",
+			Want: `{"map":[{"k":{"bytes":"02"},"v":{"int":2}},{"k":{"bytes":"01"},"v":{"int":1}}]}`,
+		},
+		"datum": {
+			CBOR: "d8799f581c9d1cbb54faf284f5d262f591b1f9201a1858de155157dad49f3881c442bd0aff",
+			Want: `{"constructor":0,"fields":[{"bytes":"9d1cbb54faf284f5d262f591b1f9201a1858de155157dad49f3881c4"},{"bytes":"bd0a"}]}`,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			got, err := DecodeToJSON(tc.CBOR)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if string(got) != tc.Want {
+				t.Fatalf("got %v; want %v", string(got), tc.Want)
+			}
+		})
+	}
+}
+
+func TestDecodeToJSON_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"hex":       "zz",
+		"trailing":  "d8798000",
+		"truncated": "d8799f41",
+		"null":      "f6",
+		"tag":       "d90100a0",
+	}
+
+	for label, data := range tests {
+		t.Run(label, func(t *testing.T) {
+			if _, err := DecodeToJSON(data); err == nil {
+				t.Fatalf("got nil; want err")
+			}
+		})
+	}
+}
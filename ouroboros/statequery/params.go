@@ -0,0 +1,157 @@
+package statequery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+// ProposedProtocolParameters holds the protocol parameter updates proposed for the next epoch
+// keyed by genesis delegate
+type ProposedProtocolParameters map[string]ProtocolParametersUpdate
+
+// ProtocolParametersUpdate holds the protocol parameters a genesis delegate proposes to change;
+// parameters not being changed are nil.  Raw holds the proposal as reported by ogmios e.g. for
+// use with DiffProtocolParameters.
+type ProtocolParametersUpdate struct {
+	MinFeeCoefficient               *uint64                     `json:"minFeeCoefficient,omitempty"`
+	MinFeeConstant                  *uint64                     `json:"minFeeConstant,omitempty"`
+	MaxBlockBodySize                *uint64                     `json:"maxBlockBodySize,omitempty"`
+	MaxBlockHeaderSize              *uint64                     `json:"maxBlockHeaderSize,omitempty"`
+	MaxTxSize                       *uint64                     `json:"maxTxSize,omitempty"`
+	StakeKeyDeposit                 *uint64                     `json:"stakeKeyDeposit,omitempty"`
+	PoolDeposit                     *uint64                     `json:"poolDeposit,omitempty"`
+	PoolRetirementEpochBound        *uint64                     `json:"poolRetirementEpochBound,omitempty"`
+	DesiredNumberOfPools            *uint64                     `json:"desiredNumberOfPools,omitempty"`
+	PoolInfluence                   *big.Rat                    `json:"poolInfluence,omitempty"`
+	MonetaryExpansion               *big.Rat                    `json:"monetaryExpansion,omitempty"`
+	TreasuryExpansion               *big.Rat                    `json:"treasuryExpansion,omitempty"`
+	DecentralizationParameter       *big.Rat                    `json:"decentralizationParameter,omitempty"`
+	MinPoolCost                     *uint64                     `json:"minPoolCost,omitempty"`
+	ProtocolVersion                 *ProtocolVersion            `json:"protocolVersion,omitempty"`
+	CoinsPerUtxoByte                *uint64                     `json:"coinsPerUtxoByte,omitempty"`
+	MaxValueSize                    *uint64                     `json:"maxValueSize,omitempty"`
+	CollateralPercentage            *uint64                     `json:"collateralPercentage,omitempty"`
+	MaxCollateralInputs             *uint64                     `json:"maxCollateralInputs,omitempty"`
+	Prices                          *ExecutionPrices            `json:"prices,omitempty"`
+	MaxExecutionUnitsPerTransaction *chainsync.ExecutionUnits   `json:"maxExecutionUnitsPerTransaction,omitempty"`
+	MaxExecutionUnitsPerBlock       *chainsync.ExecutionUnits   `json:"maxExecutionUnitsPerBlock,omitempty"`
+	CostModels                      map[string]map[string]int64 `json:"costModels,omitempty"`
+	Raw                             json.RawMessage             `json:"-"`
+}
+
+// ProtocolVersion identifies a version of the protocol
+type ProtocolVersion struct {
+	Major uint64  `json:"major"`
+	Minor uint64  `json:"minor"`
+	Patch *uint64 `json:"patch,omitempty"`
+}
+
+// ExecutionPrices holds the price of memory and cpu steps in lovelace
+type ExecutionPrices struct {
+	Memory *big.Rat `json:"memory"`
+	Steps  *big.Rat `json:"steps"`
+}
+
+func (p *ProtocolParametersUpdate) UnmarshalJSON(data []byte) error {
+	type update ProtocolParametersUpdate
+	var v update
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("failed to decode protocol parameters update: %w", err)
+	}
+	*p = ProtocolParametersUpdate(v)
+	p.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// ParamChange describes a protocol parameter that differs between two parameter sets
+type ParamChange struct {
+	Path string          // Path to the parameter, dot separated e.g. costModels.plutus:v1.bData-cpu-arguments
+	Old  json.RawMessage // Old value; nil if the parameter was added
+	New  json.RawMessage // New value; nil if the parameter was removed
+}
+
+// DiffProtocolParameters returns the parameters, sorted by path, that differ between from and
+// to as returned by CurrentProtocolParameters.  Nested objects, such as cost models, are
+// compared field by field while arrays are compared as a whole.  As proposals only list the
+// parameters being updated, when to comes from ProposedProtocolParameters, parameters absent
+// from to should be read as unchanged rather than removed.
+func DiffProtocolParameters(from, to json.RawMessage) ([]ParamChange, error) {
+	var a, b interface{}
+	if err := decodeNumbers(from, &a); err != nil {
+		return nil, fmt.Errorf("failed to decode protocol parameters: %w", err)
+	}
+	if err := decodeNumbers(to, &b); err != nil {
+		return nil, fmt.Errorf("failed to decode protocol parameters: %w", err)
+	}
+
+	var changes []ParamChange
+	if err := diffValues("", a, b, &changes); err != nil {
+		return nil, err
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// decodeNumbers decodes data preserving the exact representation of numbers
+func decodeNumbers(data json.RawMessage, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func diffValues(path string, a, b interface{}, changes *[]ParamChange) error {
+	objA, okA := a.(map[string]interface{})
+	objB, okB := b.(map[string]interface{})
+	if okA && okB {
+		for key, va := range objA {
+			if err := diffValues(joinPath(path, key), va, objB[key], changes); err != nil {
+				return err
+			}
+		}
+		for key, vb := range objB {
+			if _, ok := objA[key]; !ok {
+				if err := diffValues(joinPath(path, key), nil, vb, changes); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	oldValue, err := encodeValue(a)
+	if err != nil {
+		return err
+	}
+	newValue, err := encodeValue(b)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(oldValue, newValue) {
+		*changes = append(*changes, ParamChange{Path: path, Old: oldValue, New: newValue})
+	}
+	return nil
+}
+
+// encodeValue returns the json encoding of v, or nil for absent (nil) values
+func encodeValue(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode protocol parameter: %w", err)
+	}
+	return data, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
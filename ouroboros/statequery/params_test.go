@@ -0,0 +1,55 @@
+package statequery
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffProtocolParameters(t *testing.T) {
+	from := json.RawMessage(`{
+		"minFeeCoefficient": 44,
+		"maxTxSize": 16384,
+		"poolInfluence": "3/10",
+		"protocolVersion": {"major": 7, "minor": 0},
+		"costModels": {"plutus:v1": {"addInteger-cpu-arguments-intercept": 205665, "bData-cpu-arguments": 1000}},
+		"coinsPerUtxoByte": 4310
+	}`)
+	to := json.RawMessage(`{
+		"minFeeCoefficient": 44,
+		"maxTxSize": 16384,
+		"poolInfluence": "3/10",
+		"protocolVersion": {"major": 8, "minor": 0},
+		"costModels": {"plutus:v1": {"addInteger-cpu-arguments-intercept": 205665, "bData-cpu-arguments": 1200}, "plutus:v2": {"bData-cpu-arguments": 1000}},
+		"maxCollateralInputs": 3
+	}`)
+
+	changes, err := DiffProtocolParameters(from, to)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	want := []struct {
+		Path, Old, New string
+	}{
+		{Path: "coinsPerUtxoByte", Old: "4310"},
+		{Path: "costModels.plutus:v1.bData-cpu-arguments", Old: "1000", New: "1200"},
+		{Path: "costModels.plutus:v2", New: `{"bData-cpu-arguments":1000}`},
+		{Path: "maxCollateralInputs", New: "3"},
+		{Path: "protocolVersion.major", Old: "7", New: "8"},
+	}
+	if got, want := len(changes), len(want); got != want {
+		t.Fatalf("got %v; want %v: %v", got, want, changes)
+	}
+	for i, w := range want {
+		if got := changes[i]; got.Path != w.Path || string(got.Old) != w.Old || string(got.New) != w.New {
+			t.Fatalf("got %v %s %s; want %v %v %v", got.Path, got.Old, got.New, w.Path, w.Old, w.New)
+		}
+	}
+
+	if changes, err := DiffProtocolParameters(from, from); err != nil || len(changes) != 0 {
+		t.Fatalf("got %v, %v; want no changes", changes, err)
+	}
+	if _, err := DiffProtocolParameters(from, json.RawMessage(`{`)); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
@@ -0,0 +1,141 @@
+package statequery
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+// PoolParameters describes the registration of a stake pool
+type PoolParameters struct {
+	ID            string        `json:"id"`
+	VRF           string        `json:"vrf"`
+	Pledge        num.Int       `json:"pledge"`
+	Cost          num.Int       `json:"cost"`
+	Margin        *big.Rat      `json:"margin"`
+	RewardAccount string        `json:"rewardAccount"`
+	Owners        []string      `json:"owners"`
+	Relays        []Relay       `json:"relays"`
+	Metadata      *PoolMetadata `json:"metadata,omitempty"`
+}
+
+// PoolMetadata references the off-chain metadata of a stake pool
+type PoolMetadata struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash"`
+}
+
+// RelayType discriminates the forms a stake pool relay may take
+type RelayType string
+
+const (
+	RelayAddress  RelayType = "address"  // RelayAddress is reached at an ipv4 and/or ipv6 address and port
+	RelayHostname RelayType = "hostname" // RelayHostname is reached by resolving the A or AAAA records of a hostname
+	RelaySRV      RelayType = "srv"      // RelaySRV is reached by resolving the SRV records of a hostname
+)
+
+// Relay describes how to reach a stake pool
+type Relay struct {
+	Type     RelayType
+	IPv4     string // IPv4 address for RelayAddress; optional
+	IPv6     string // IPv6 address for RelayAddress; optional
+	Hostname string // Hostname for RelayHostname and RelaySRV
+	Port     uint16 // Port for RelayAddress and RelayHostname; optional
+}
+
+// HostPorts returns the host:port endpoints of address and hostname relays; SRV relays and
+// relays without a port have no endpoints as the port must be resolved
+func (r Relay) HostPorts() []string {
+	if r.Port == 0 {
+		return nil
+	}
+	port := strconv.Itoa(int(r.Port))
+
+	var hostPorts []string
+	switch r.Type {
+	case RelayAddress:
+		for _, host := range []string{r.IPv4, r.IPv6} {
+			if host != "" {
+				hostPorts = append(hostPorts, net.JoinHostPort(host, port))
+			}
+		}
+	case RelayHostname:
+		hostPorts = append(hostPorts, net.JoinHostPort(r.Hostname, port))
+	}
+	return hostPorts
+}
+
+type relayJSON struct {
+	IPv4     *string `json:"ipv4,omitempty"`
+	IPv6     *string `json:"ipv6,omitempty"`
+	Hostname *string `json:"hostname,omitempty"`
+	DNS      *string `json:"dns,omitempty"`
+	Port     *uint16 `json:"port"`
+}
+
+func (r Relay) MarshalJSON() ([]byte, error) {
+	var v relayJSON
+	if r.Port > 0 {
+		v.Port = &r.Port
+	}
+	switch r.Type {
+	case RelayAddress:
+		if r.IPv4 != "" {
+			v.IPv4 = &r.IPv4
+		}
+		if r.IPv6 != "" {
+			v.IPv6 = &r.IPv6
+		}
+	case RelayHostname:
+		v.Hostname = &r.Hostname
+	case RelaySRV:
+		// multi host name relays omit the port field entirely
+		return json.Marshal(map[string]string{"hostname": r.Hostname})
+	default:
+		return nil, fmt.Errorf("unknown relay type, %v", r.Type)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes the relay; ogmios reports multi host name (SRV) relays as a hostname
+// without a port field, or as a dns entry, whereas single host name relays carry a port field
+// albeit possibly null
+func (r *Relay) UnmarshalJSON(data []byte) error {
+	var v relayJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("failed to decode relay: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to decode relay: %w", err)
+	}
+	_, hasPort := fields["port"]
+
+	*r = Relay{}
+	if v.Port != nil {
+		r.Port = *v.Port
+	}
+	switch {
+	case v.IPv4 != nil || v.IPv6 != nil:
+		r.Type = RelayAddress
+		if v.IPv4 != nil {
+			r.IPv4 = *v.IPv4
+		}
+		if v.IPv6 != nil {
+			r.IPv6 = *v.IPv6
+		}
+	case v.DNS != nil:
+		r.Type, r.Hostname = RelaySRV, *v.DNS
+	case v.Hostname != nil && !hasPort:
+		r.Type, r.Hostname = RelaySRV, *v.Hostname
+	case v.Hostname != nil:
+		r.Type, r.Hostname = RelayHostname, *v.Hostname
+	default:
+		return fmt.Errorf("failed to decode relay: unknown relay, %v", string(data))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package statequery
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRelay_JSON(t *testing.T) {
+	tests := map[string]struct {
+		Data      string
+		Want      Relay
+		HostPorts []string
+	}{
+		"ipv4": {
+			Data:      `{"ipv4":"192.0.2.1","ipv6":null,"port":3001}`,
+			Want:      Relay{Type: RelayAddress, IPv4: "192.0.2.1", Port: 3001},
+			HostPorts: []string{"192.0.2.1:3001"},
+		},
+		"ipv4 and ipv6": {
+			Data:      `{"ipv4":"192.0.2.1","ipv6":"2001:db8::1","port":3001}`,
+			Want:      Relay{Type: RelayAddress, IPv4: "192.0.2.1", IPv6: "2001:db8::1", Port: 3001},
+			HostPorts: []string{"192.0.2.1:3001", "[2001:db8::1]:3001"},
+		},
+		"hostname": {
+			Data:      `{"hostname":"relay.example.com","port":3001}`,
+			Want:      Relay{Type: RelayHostname, Hostname: "relay.example.com", Port: 3001},
+			HostPorts: []string{"relay.example.com:3001"},
+		},
+		"hostname without port": {
+			Data: `{"hostname":"relay.example.com","port":null}`,
+			Want: Relay{Type: RelayHostname, Hostname: "relay.example.com"},
+		},
+		"multi host name": {
+			Data: `{"hostname":"_cardano._tcp.example.com"}`,
+			Want: Relay{Type: RelaySRV, Hostname: "_cardano._tcp.example.com"},
+		},
+		"dns": {
+			Data: `{"dns":"_cardano._tcp.example.com"}`,
+			Want: Relay{Type: RelaySRV, Hostname: "_cardano._tcp.example.com"},
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			var got Relay
+			if err := json.Unmarshal([]byte(tc.Data), &got); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got != tc.Want {
+				t.Fatalf("got %#v; want %#v", got, tc.Want)
+			}
+			if hostPorts := got.HostPorts(); !reflect.DeepEqual(hostPorts, tc.HostPorts) {
+				t.Fatalf("got %v; want %v", hostPorts, tc.HostPorts)
+			}
+
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			var decoded Relay
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if decoded != tc.Want {
+				t.Fatalf("got %#v; want %#v", decoded, tc.Want)
+			}
+		})
+	}
+
+	var relay Relay
+	if err := json.Unmarshal([]byte(`{"port":3001}`), &relay); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func TestPoolParameters_JSON(t *testing.T) {
+	data := []byte(`{
+		"id": "pool1abc",
+		"vrf": "vrf",
+		"pledge": 500000000,
+		"cost": 340000000,
+		"margin": "1/50",
+		"rewardAccount": "stake1abc",
+		"owners": ["owner"],
+		"relays": [{"ipv4":"192.0.2.1","ipv6":null,"port":3001},{"hostname":"_cardano._tcp.example.com"}],
+		"metadata": {"url":"https://example.com/pool.json","hash":"hash"}
+	}`)
+
+	var pool PoolParameters
+	if err := json.Unmarshal(data, &pool); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := pool.Margin.String(), "1/50"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := pool.Pledge.Int64(), int64(500000000); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := len(pool.Relays), 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := pool.Relays[1].Type, RelaySRV; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
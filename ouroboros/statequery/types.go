@@ -3,9 +3,11 @@ package statequery
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
 )
 
 type EraStart struct {
@@ -14,6 +16,29 @@ type EraStart struct {
 	Epoch uint64        `json:"epoch,omitempty"`
 }
 
+// PoolDistribution describes the share of the stake delegated to a pool
+type PoolDistribution struct {
+	Stake *big.Rat `json:"stake"`
+	VRF   string   `json:"vrf"`
+}
+
+// RewardsProvenance provides the details used to compute rewards for the current epoch
+type RewardsProvenance struct {
+	DesiredNumberOfPools uint64                           `json:"desiredNumberOfPools"`
+	PoolInfluence        *big.Rat                         `json:"poolInfluence"`
+	TotalRewards         num.Int                          `json:"totalRewards"`
+	ActiveStake          num.Int                          `json:"activeStake"`
+	Pools                map[string]RewardsProvenancePool `json:"pools"`
+}
+
+// RewardsProvenancePool provides the per pool details used to compute rewards
+type RewardsProvenancePool struct {
+	Stake                  num.Int         `json:"stake"`
+	OwnerStake             num.Int         `json:"ownerStake"`
+	ApproximatePerformance float64         `json:"approximatePerformance"`
+	PoolParameters         json.RawMessage `json:"poolParameters,omitempty"`
+}
+
 type Utxo struct {
 	TxIn  chainsync.TxIn
 	TxOut chainsync.TxOut
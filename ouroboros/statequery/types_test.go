@@ -39,3 +39,40 @@ func TestUtxo_MarshalJSON(t *testing.T) {
 		t.Fatalf("got %#v; want %#v", got, want)
 	}
 }
+
+func TestRewardsProvenance_UnmarshalJSON(t *testing.T) {
+	data := []byte(`{
+		"desiredNumberOfPools": 500,
+		"poolInfluence": "3/10",
+		"totalRewards": 18446744073709551616,
+		"activeStake": 25000000000000000,
+		"pools": {
+			"pool1": {"stake": 100, "ownerStake": 10, "approximatePerformance": 0.95, "poolParameters": {"pledge": 10}}
+		}
+	}`)
+
+	var got RewardsProvenance
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	if got, want := got.DesiredNumberOfPools, uint64(500); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := got.PoolInfluence.String(), "3/10"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := got.TotalRewards.String(), "18446744073709551616"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	pool, ok := got.Pools["pool1"]
+	if !ok {
+		t.Fatalf("got false; want true")
+	}
+	if got, want := pool.Stake.Uint64(), uint64(100); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := pool.ApproximatePerformance, 0.95; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
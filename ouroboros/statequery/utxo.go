@@ -0,0 +1,58 @@
+package statequery
+
+import (
+	"fmt"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+// GroupUtxosByAddress partitions utxos by the address holding them, preserving order
+func GroupUtxosByAddress(utxos []Utxo) map[string][]Utxo {
+	groups := map[string][]Utxo{}
+	for _, utxo := range utxos {
+		address := utxo.TxOut.Address
+		groups[address] = append(groups[address], utxo)
+	}
+	return groups
+}
+
+// GroupUtxosByPolicy partitions utxos by the policy ids of the assets they hold, preserving
+// order.  A utxo holding assets of several policies appears in each group; ada only utxos
+// and zero amounts are omitted.
+func GroupUtxosByPolicy(utxos []Utxo) map[string][]Utxo {
+	groups := map[string][]Utxo{}
+	for _, utxo := range utxos {
+		seen := map[string]struct{}{}
+		for assetID, amount := range utxo.TxOut.Value.Assets {
+			policyID := assetID.PolicyID()
+			if _, ok := seen[policyID]; ok || amount.BigInt().Sign() == 0 {
+				continue
+			}
+			seen[policyID] = struct{}{}
+			groups[policyID] = append(groups[policyID], utxo)
+		}
+	}
+	return groups
+}
+
+// ReferenceScripts collects the scripts held by resolved reference inputs keyed by script
+// hash; utxos without a script are ignored
+func ReferenceScripts(resolved []Utxo) (map[string]chainsync.Script, error) {
+	scripts := map[string]chainsync.Script{}
+	for _, utxo := range resolved {
+		if len(utxo.TxOut.Script) == 0 || string(utxo.TxOut.Script) == "null" {
+			continue
+		}
+
+		script, err := chainsync.ParseScript(utxo.TxOut.Script)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reference script of %v#%v: %w", utxo.TxIn.TxHash, utxo.TxIn.Index, err)
+		}
+		hash, err := script.Hash()
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash reference script of %v#%v: %w", utxo.TxIn.TxHash, utxo.TxIn.Index, err)
+		}
+		scripts[hash] = script
+	}
+	return scripts, nil
+}
@@ -0,0 +1,103 @@
+package statequery
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
+)
+
+func testUtxo(txHash, address string, assets ...string) Utxo {
+	value := chainsync.Value{Coins: num.Int64(2000000), Assets: map[chainsync.AssetID]num.Int{}}
+	for _, asset := range assets {
+		value.Assets[chainsync.AssetID(asset)] = num.Int64(1)
+	}
+	return Utxo{
+		TxIn:  chainsync.TxIn{TxHash: txHash},
+		TxOut: chainsync.TxOut{Address: address, Value: value},
+	}
+}
+
+func txHashes(utxos []Utxo) []string {
+	var hashes []string
+	for _, utxo := range utxos {
+		hashes = append(hashes, utxo.TxIn.TxHash)
+	}
+	return hashes
+}
+
+func TestGroupUtxosByAddress(t *testing.T) {
+	utxos := []Utxo{
+		testUtxo("a", "addr1"),
+		testUtxo("b", "addr2"),
+		testUtxo("c", "addr1"),
+	}
+
+	groups := GroupUtxosByAddress(utxos)
+	if got, want := len(groups), 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := txHashes(groups["addr1"]), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := txHashes(groups["addr2"]), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if got := GroupUtxosByAddress(nil); len(got) != 0 {
+		t.Fatalf("got %v; want empty", got)
+	}
+}
+
+func TestGroupUtxosByPolicy(t *testing.T) {
+	zero := testUtxo("d", "addr1", "p3.01")
+	zero.TxOut.Value.Assets["p3.01"] = num.Int64(0)
+
+	utxos := []Utxo{
+		testUtxo("a", "addr1", "p1.01", "p1.02"),
+		testUtxo("b", "addr1", "p1.01", "p2"),
+		testUtxo("c", "addr2"),
+		zero,
+	}
+
+	groups := GroupUtxosByPolicy(utxos)
+	if got, want := len(groups), 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := txHashes(groups["p1"]), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := txHashes(groups["p2"]), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestReferenceScripts(t *testing.T) {
+	native := testUtxo("a", "addr1")
+	native.TxOut.Script = json.RawMessage(`{"native":"b5b7c56d4ee83e986f7a60ef04ecf142b4b33d0afa32f69a1004f2d8"}`)
+	plutus := testUtxo("b", "addr1")
+	plutus.TxOut.Script = json.RawMessage(`{"plutus:v2":"4d01000033222220051200120011"}`)
+	none := testUtxo("c", "addr1")
+
+	scripts, err := ReferenceScripts([]Utxo{native, plutus, none})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(scripts), 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := scripts["f295059da7ca2827fa7b8961c83a74e512d353bfe4f613ef0b8e4a09"].Language, chainsync.ScriptNative; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := scripts["793f8c8cffba081b2a56462fc219cc8fe652d6a338b62c7b134876e7"].Language, chainsync.ScriptPlutusV2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	invalid := testUtxo("d", "addr1")
+	invalid.TxOut.Script = json.RawMessage(`{"plutus:v3":"00"}`)
+	if _, err := ReferenceScripts([]Utxo{invalid}); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
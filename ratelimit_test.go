@@ -0,0 +1,87 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_rateLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("burst", func(t *testing.T) {
+		limiter := newRateLimiter(1, 3)
+		begin := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := limiter.wait(ctx); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+		}
+		if elapsed := time.Since(begin); elapsed > 100*time.Millisecond {
+			t.Fatalf("got %v; want burst without waiting", elapsed)
+		}
+	})
+
+	t.Run("waits", func(t *testing.T) {
+		limiter := newRateLimiter(20, 1)
+		begin := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := limiter.wait(ctx); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+		}
+		if elapsed := time.Since(begin); elapsed < 90*time.Millisecond {
+			t.Fatalf("got %v; want at least 100ms", elapsed)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		limiter := newRateLimiter(0.01, 1)
+		if err := limiter.wait(ctx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if err := limiter.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got %v; want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var limiter *rateLimiter
+		if err := limiter.wait(ctx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+}
+
+func TestClient_queryRateLimit(t *testing.T) {
+	endpoint, _ := queryServer(t, `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"Query","result":42}`)
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithRateLimit(0.01, 1))
+
+	if _, err := client.CurrentEpoch(context.Background()); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.CurrentEpoch(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v; want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -18,9 +18,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
+	"time"
+
+	"github.com/gorilla/websocket"
 
 	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync/num"
 	"github.com/SundaeSwap-finance/ogmigo/ouroboros/statequery"
 )
 
@@ -37,6 +42,102 @@ func (c *Client) ChainTip(ctx context.Context) (chainsync.Point, error) {
 	return content.Result, nil
 }
 
+// NetworkTip returns the tip of the chain known to the node, which may be ahead of the
+// ledger tip returned by ChainTip while the node is catching up
+func (c *Client) NetworkTip(ctx context.Context) (chainsync.Point, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "chainTip"})
+		content struct{ Result chainsync.Point }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return chainsync.Point{}, err
+	}
+
+	return content.Result, nil
+}
+
+// NetworkBlockHeight returns the height of the most recent block known to the node; ok is false
+// if the node has yet to see any block i.e. the chain is at origin
+func (c *Client) NetworkBlockHeight(ctx context.Context) (height uint64, ok bool, err error) {
+	var (
+		payload = makePayload("Query", Map{"query": "blockHeight"})
+		content struct{ Result json.RawMessage }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return 0, false, err
+	}
+
+	if string(content.Result) == `"origin"` {
+		return 0, false, nil
+	}
+	if err := json.Unmarshal(content.Result, &height); err != nil {
+		return 0, false, fmt.Errorf("failed to decode block height: %w", err)
+	}
+	return height, true, nil
+}
+
+var (
+	syncPollInterval    = time.Second      // syncPollInterval is the initial delay between WaitUntilSynced polls
+	maxSyncPollInterval = 30 * time.Second // maxSyncPollInterval caps the backoff of WaitUntilSynced
+)
+
+// WaitUntilSynced blocks until the ledger tip is within tolerance slots of the network tip,
+// polling with exponential backoff.  Query errors are logged and retried until ctx is done.
+func (c *Client) WaitUntilSynced(ctx context.Context, tolerance uint64) error {
+	interval := syncPollInterval
+	for {
+		behind, err := c.slotsBehind(ctx)
+		if err == nil && behind <= tolerance {
+			return nil
+		}
+		if err != nil {
+			c.options.logger.Info("failed to query tips: will retry",
+				KV("delay", interval.String()),
+				KV("err", err.Error()),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("failed to wait until synced: %w", err)
+			}
+			return fmt.Errorf("failed to wait until synced: %v slots behind: %w", behind, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		if interval *= 2; interval > maxSyncPollInterval {
+			interval = maxSyncPollInterval
+		}
+	}
+}
+
+// slotsBehind returns the number of slots the ledger tip trails the network tip
+func (c *Client) slotsBehind(ctx context.Context) (uint64, error) {
+	ledger, err := c.ChainTip(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query ledger tip: %w", err)
+	}
+	network, err := c.NetworkTip(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query network tip: %w", err)
+	}
+
+	var ledgerSlot, networkSlot uint64 // origin
+	if ps, ok := ledger.PointStruct(); ok {
+		ledgerSlot = ps.Slot
+	}
+	if ps, ok := network.PointStruct(); ok {
+		networkSlot = ps.Slot
+	}
+	if ledgerSlot >= networkSlot {
+		return 0, nil
+	}
+	return networkSlot - ledgerSlot, nil
+}
+
 func (c *Client) CurrentEpoch(ctx context.Context) (uint64, error) {
 	var (
 		payload = makePayload("Query", Map{"query": "currentEpoch"})
@@ -63,6 +164,60 @@ func (c *Client) CurrentProtocolParameters(ctx context.Context) (json.RawMessage
 	return content.Result, nil
 }
 
+// GenesisEras lists the eras whose genesis configuration may be queried via GenesisConfig.
+// Ogmios v5 does not expose a conway genesis configuration.
+var GenesisEras = []string{"byron", "shelley", "alonzo"}
+
+// GenesisConfig returns the genesis configuration of the given era e.g. shelley
+func (c *Client) GenesisConfig(ctx context.Context, era string) (json.RawMessage, error) {
+	var (
+		payload = makePayload("Query", Map{"query": Map{"genesisConfig": era}})
+		content struct{ Result json.RawMessage }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query %v genesis config: %w", era, err)
+	}
+
+	return content.Result, nil
+}
+
+// AllGenesisConfigs returns the genesis configuration of each era in GenesisEras keyed by era
+func (c *Client) AllGenesisConfigs(ctx context.Context) (map[string]json.RawMessage, error) {
+	configs := map[string]json.RawMessage{}
+	for _, era := range GenesisEras {
+		config, err := c.GenesisConfig(ctx, era)
+		if err != nil {
+			return nil, err
+		}
+		configs[era] = config
+	}
+	return configs, nil
+}
+
+// GenesisInitialFunds returns the initial funds, keyed by address, allocated by the byron and
+// shelley genesis configurations.  Initial funds are typically only present on test networks.
+func (c *Client) GenesisInitialFunds(ctx context.Context) (map[string]chainsync.Value, error) {
+	funds := map[string]chainsync.Value{}
+	for _, era := range []string{"byron", "shelley"} {
+		config, err := c.GenesisConfig(ctx, era)
+		if err != nil {
+			return nil, err
+		}
+
+		var genesis struct {
+			InitialFunds map[string]num.Int `json:"initialFunds"`
+		}
+		if err := json.Unmarshal(config, &genesis); err != nil {
+			return nil, fmt.Errorf("failed to decode %v initial funds: %w", era, err)
+		}
+		for address, coins := range genesis.InitialFunds {
+			funds[address] = chainsync.Add(funds[address], chainsync.Value{Coins: coins})
+		}
+	}
+	return funds, nil
+}
+
 type EraHistory struct {
 	Summaries []EraSummary
 }
@@ -85,6 +240,42 @@ type EraParameters struct {
 	SafeZone    uint64 `json:"safeZone"`
 }
 
+// SlotsBetween returns the number of slots between slots a and b.  Slots are numbered
+// contiguously across eras, so no era history is needed; use EraHistory to convert the
+// result to a duration, as slot lengths differ between eras.
+func SlotsBetween(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// EpochSlots returns the first and last slot of the given epoch along with the slot length of
+// its era.  Ogmios v5 reports slot lengths in seconds.
+func (h *EraHistory) EpochSlots(epoch uint64) (first, last uint64, slotLength time.Duration, err error) {
+	for i, summary := range h.Summaries {
+		// the final summary is open ended as the chain may extend beyond its safe zone
+		if epoch < summary.Start.Epoch || (epoch >= summary.End.Epoch && i < len(h.Summaries)-1) {
+			continue
+		}
+		if summary.Parameters.EpochLength == 0 {
+			return 0, 0, 0, fmt.Errorf("failed to compute epoch slots: era starting at epoch %v has no epoch length", summary.Start.Epoch)
+		}
+
+		first = summary.Start.Slot + (epoch-summary.Start.Epoch)*summary.Parameters.EpochLength
+		last = first + summary.Parameters.EpochLength - 1
+		slotLength = time.Duration(summary.Parameters.SlotLength) * time.Second
+		return first, last, slotLength, nil
+	}
+	return 0, 0, 0, fmt.Errorf("failed to compute epoch slots: epoch %v not found in era history", epoch)
+}
+
+// ApproxBlocks estimates the number of blocks produced over the given number of slots
+// using the active slot coefficient from the shelley genesis e.g. 0.05 on mainnet
+func ApproxBlocks(slots uint64, activeSlotCoeff float64) uint64 {
+	return uint64(math.Round(float64(slots) * activeSlotCoeff))
+}
+
 func (c *Client) EraSummaries(ctx context.Context) (*EraHistory, error) {
 	var (
 		payload = makePayload("Query", Map{"query": "eraSummaries"})
@@ -105,6 +296,78 @@ func (c *Client) EraSummaries(ctx context.Context) (*EraHistory, error) {
 	}, nil
 }
 
+// CachedEraHistory returns the era history, querying ogmios only if the history cached by
+// a prior call is older than ttl.  As era history only changes at hard forks, a generous ttl
+// makes slot and time conversions cheap.  The returned history is shared and must not be
+// modified.
+func (c *Client) CachedEraHistory(ctx context.Context, ttl time.Duration) (*EraHistory, error) {
+	c.mutex.Lock()
+	history, at := c.eraHistory, c.eraHistoryAt
+	c.mutex.Unlock()
+
+	if history != nil && time.Since(at) < ttl {
+		return history, nil
+	}
+
+	history, err := c.EraSummaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.eraHistory, c.eraHistoryAt = history, time.Now()
+	c.mutex.Unlock()
+
+	return history, nil
+}
+
+// EpochDetails describes the current epoch and how long remains until the next one
+type EpochDetails struct {
+	Epoch         uint64
+	FirstSlot     uint64
+	LastSlot      uint64
+	TimeRemaining time.Duration // TimeRemaining until the first slot of the next epoch, as of the ledger tip
+}
+
+// EpochDetails combines the current epoch with the era history to find the boundaries of the
+// current epoch
+func (c *Client) EpochDetails(ctx context.Context) (EpochDetails, error) {
+	epoch, err := c.CurrentEpoch(ctx)
+	if err != nil {
+		return EpochDetails{}, fmt.Errorf("failed to query current epoch: %w", err)
+	}
+
+	tip, err := c.ChainTip(ctx)
+	if err != nil {
+		return EpochDetails{}, fmt.Errorf("failed to query chain tip: %w", err)
+	}
+
+	history, err := c.EraSummaries(ctx)
+	if err != nil {
+		return EpochDetails{}, fmt.Errorf("failed to query era summaries: %w", err)
+	}
+
+	first, last, slotLength, err := history.EpochSlots(epoch)
+	if err != nil {
+		return EpochDetails{}, err
+	}
+
+	details := EpochDetails{
+		Epoch:     epoch,
+		FirstSlot: first,
+		LastSlot:  last,
+	}
+	var slot uint64 // origin
+	if ps, ok := tip.PointStruct(); ok {
+		slot = ps.Slot
+	}
+	if slot <= last {
+		details.TimeRemaining = time.Duration(last+1-slot) * slotLength
+	}
+
+	return details, nil
+}
+
 func (c *Client) EraStart(ctx context.Context) (statequery.EraStart, error) {
 	var (
 		payload = makePayload("Query", Map{"query": "eraStart"})
@@ -127,10 +390,31 @@ func (c *Client) UtxosByAddress(ctx context.Context, addresses ...string) ([]sta
 	if err := c.query(ctx, payload, &content); err != nil {
 		return nil, fmt.Errorf("failed to query utxos by address: %w", err)
 	}
+	if err := c.checkUtxoNetwork(content.Result); err != nil {
+		return nil, err
+	}
 
 	return content.Result, nil
 }
 
+// UtxosByAddressWithAsset returns the utxos held by the addresses that contain a non-zero
+// amount of the given asset; assetName is hex encoded.  Ogmios cannot filter by asset, so
+// every utxo at the addresses is retrieved and filtered by the client.
+func (c *Client) UtxosByAddressWithAsset(ctx context.Context, addresses []string, policyID, assetName string) ([]statequery.Utxo, error) {
+	utxos, err := c.UtxosByAddress(ctx, addresses...)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []statequery.Utxo
+	for _, utxo := range utxos {
+		if utxo.TxOut.Value.HasAsset(policyID, assetName) {
+			filtered = append(filtered, utxo)
+		}
+	}
+	return filtered, nil
+}
+
 func (c *Client) UtxosByTxIn(ctx context.Context, txIns ...chainsync.TxIn) ([]statequery.Utxo, error) {
 	var (
 		payload = makePayload("Query", Map{"query": Map{"utxo": txIns}})
@@ -140,6 +424,139 @@ func (c *Client) UtxosByTxIn(ctx context.Context, txIns ...chainsync.TxIn) ([]st
 	if err := c.query(ctx, payload, &content); err != nil {
 		return nil, fmt.Errorf("failed to query utxos by address: %w", err)
 	}
+	if err := c.checkUtxoNetwork(content.Result); err != nil {
+		return nil, err
+	}
 
 	return content.Result, nil
 }
+
+// utxoSnapshotPageSize is the maximum number of utxos passed to each UtxoSnapshot callback
+var utxoSnapshotPageSize = 1000
+
+// UtxoSnapshot acquires the ledger state at point and passes the utxos held by the addresses,
+// or the entire utxo set if no addresses are given, to cb in pages.  Ogmios returns the utxo
+// set in a single response, so pages are formed by the client; the ledger state is released
+// before cb is first invoked.  The point must be recent enough for ogmios to acquire it,
+// typically within the last k blocks.
+func (c *Client) UtxoSnapshot(ctx context.Context, point chainsync.Point, cb func([]statequery.Utxo) error, addresses ...string) error {
+	var query interface{} = "utxo"
+	if len(addresses) > 0 {
+		query = Map{"utxo": addresses}
+	}
+
+	if err := c.options.limiter.wait(ctx); err != nil {
+		return fmt.Errorf("failed to query utxo snapshot: %w", err)
+	}
+
+	var utxos []statequery.Utxo
+	err := c.withConn(ctx, func(conn *websocket.Conn) error {
+		var (
+			payload  = makePayload("Acquire", Map{"point": point})
+			acquired struct {
+				Result struct {
+					AcquireFailure *struct{ Failure string }
+				}
+			}
+		)
+		method := c.mirror(payload)
+		if err := c.exchange(conn, payload, &acquired); err != nil {
+			return fmt.Errorf("%v: %w", method, err)
+		}
+		if failure := acquired.Result.AcquireFailure; failure != nil {
+			return fmt.Errorf("%v: %v", method, failure.Failure)
+		}
+
+		var content struct{ Result []statequery.Utxo }
+		payload = makePayload("Query", Map{"query": query})
+		method = c.mirror(payload)
+		if err := c.exchange(conn, payload, &content); err != nil {
+			return fmt.Errorf("%v: %w", method, err)
+		}
+		utxos = content.Result
+
+		payload = makePayload("Release", Map{})
+		method = c.mirror(payload)
+		if err := c.exchange(conn, payload, nil); err != nil {
+			return fmt.Errorf("%v: %w", method, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query utxo snapshot: %w", err)
+	}
+	if err := c.checkUtxoNetwork(utxos); err != nil {
+		return err
+	}
+
+	for len(utxos) > 0 {
+		n := utxoSnapshotPageSize
+		if n > len(utxos) {
+			n = len(utxos)
+		}
+		if err := cb(utxos[:n]); err != nil {
+			return err
+		}
+		utxos = utxos[n:]
+	}
+	return nil
+}
+
+// checkUtxoNetwork verifies the utxos belong to the network configured via WithNetwork, if any
+func (c *Client) checkUtxoNetwork(utxos []statequery.Utxo) error {
+	if c.options.network == "" {
+		return nil
+	}
+	for _, utxo := range utxos {
+		if err := chainsync.CheckAddressNetwork(utxo.TxOut.Address, c.options.network); err != nil {
+			return fmt.Errorf("utxo %v#%v: %w", utxo.TxIn.TxHash, utxo.TxIn.Index, err)
+		}
+	}
+	return nil
+}
+
+// PoolParameters returns the registered parameters of the given stake pools keyed by pool id
+func (c *Client) PoolParameters(ctx context.Context, poolIDs ...string) (map[string]statequery.PoolParameters, error) {
+	var (
+		payload = makePayload("Query", Map{"query": Map{"poolParameters": poolIDs}})
+		content struct {
+			Result map[string]statequery.PoolParameters
+		}
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query pool parameters: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// PoolIDs returns the ids of all registered stake pools
+func (c *Client) PoolIDs(ctx context.Context) ([]string, error) {
+	var (
+		payload = makePayload("Query", Map{"query": "poolIds"})
+		content struct{ Result []string }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to query pool ids: %w", err)
+	}
+
+	return content.Result, nil
+}
+
+// StakePools returns the registered parameters of the given stake pools, or of every registered
+// pool if no ids are provided, keyed by pool id
+func (c *Client) StakePools(ctx context.Context, ids ...string) (map[string]statequery.PoolParameters, error) {
+	if len(ids) == 0 {
+		poolIDs, err := c.PoolIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(poolIDs) == 0 {
+			return map[string]statequery.PoolParameters{}, nil
+		}
+		ids = poolIDs
+	}
+	return c.PoolParameters(ctx, ids...)
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/statequery"
+)
+
+// ledgerQueries lists the state queries, taking no arguments, supported by LedgerQuery; must
+// remain sorted
+var ledgerQueries = []string{
+	"blockHeight",
+	"chainTip",
+	"currentEpoch",
+	"currentProtocolParameters",
+	"eraStart",
+	"eraSummaries",
+	"ledgerTip",
+	"poolIds",
+	"proposedProtocolParameters",
+	"rewardsProvenance'",
+	"stakeDistribution",
+}
+
+// LedgerQueries returns the sorted names of the state queries, taking no arguments, supported
+// by LedgerQuery
+func LedgerQueries() []string {
+	return append([]string(nil), ledgerQueries...)
+}
+
+// LedgerQuery executes the named state query and decodes the result into v.  An error is
+// returned for queries not listed by LedgerQueries.
+func (c *Client) LedgerQuery(ctx context.Context, query string, v interface{}) error {
+	if index := sort.SearchStrings(ledgerQueries, query); index == len(ledgerQueries) || ledgerQueries[index] != query {
+		return fmt.Errorf("unsupported ledger query, %v: supported queries are %v", query, ledgerQueries)
+	}
+
+	var (
+		payload = makePayload("Query", Map{"query": query})
+		content struct{ Result json.RawMessage }
+	)
+
+	if err := c.query(ctx, payload, &content); err != nil {
+		return err
+	}
+
+	if v != nil {
+		if err := json.Unmarshal(content.Result, v); err != nil {
+			return fmt.Errorf("failed to decode %v: %w", query, err)
+		}
+	}
+
+	return nil
+}
+
+// ProposedProtocolParameters returns the protocol parameter updates proposed for the next
+// epoch keyed by genesis delegate
+func (c *Client) ProposedProtocolParameters(ctx context.Context) (statequery.ProposedProtocolParameters, error) {
+	var result statequery.ProposedProtocolParameters
+	if err := c.LedgerQuery(ctx, "proposedProtocolParameters", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RewardsProvenance returns the details used to compute the rewards of the current epoch
+func (c *Client) RewardsProvenance(ctx context.Context) (statequery.RewardsProvenance, error) {
+	var result statequery.RewardsProvenance
+	if err := c.LedgerQuery(ctx, "rewardsProvenance'", &result); err != nil {
+		return statequery.RewardsProvenance{}, fmt.Errorf("failed to query rewards provenance: %w", err)
+	}
+	return result, nil
+}
+
+// StakeDistribution returns the live stake distribution keyed by pool id
+func (c *Client) StakeDistribution(ctx context.Context) (map[string]statequery.PoolDistribution, error) {
+	var result map[string]statequery.PoolDistribution
+	if err := c.LedgerQuery(ctx, "stakeDistribution", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogmigo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLedgerQueries(t *testing.T) {
+	queries := LedgerQueries()
+	if !sort.StringsAreSorted(queries) {
+		t.Fatalf("got unsorted LedgerQueries; want sorted")
+	}
+	for _, want := range []string{"blockHeight", "chainTip", "poolIds"} {
+		if index := sort.SearchStrings(queries, want); index == len(queries) || queries[index] != want {
+			t.Fatalf("got %v; want contains %v", queries, want)
+		}
+	}
+
+	queries[0] = "zzz"
+	if got := LedgerQueries()[0]; got == "zzz" {
+		t.Fatalf("got %v; want LedgerQueries to return a copy", got)
+	}
+}
+
+func TestClient_LedgerQuery(t *testing.T) {
+	t.Run("unsupported", func(t *testing.T) {
+		client := New(WithEndpoint("ws://127.0.0.1:0"), WithLogger(NopLogger))
+		err := client.LedgerQuery(context.Background(), "nonces", nil)
+		if err == nil {
+			t.Fatalf("got nil; want err")
+		}
+		if got, want := err.Error(), "unsupported ledger query, nonces"; !strings.HasPrefix(got, want) {
+			t.Fatalf("got %v; want prefix %v", got, want)
+		}
+	})
+
+	t.Run("stakeDistribution", func(t *testing.T) {
+		endpoint, requests := queryServer(t, `{"type":"jsonwsp/response","methodname":"Query","result":{"pool1":{"stake":"1/100","vrf":"abc"}}}`)
+
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+		got, err := client.StakeDistribution(context.Background())
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, want := got["pool1"].Stake.String(), "1/100"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := got["pool1"].VRF, "abc"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := string(<-requests), `"query":"stakeDistribution"`; !strings.Contains(got, want) {
+			t.Fatalf("got %v; want contains %v", got, want)
+		}
+	})
+	t.Run("proposedProtocolParameters", func(t *testing.T) {
+		endpoint, _ := queryServer(t, `{"type":"jsonwsp/response","methodname":"Query","result":{"637f2e950b0fd8f8e3e811c5fbeb19e411e7a2bf37272b84b29c1a0b":{"maxBlockBodySize":90112,"poolInfluence":"3/10","protocolVersion":{"major":8,"minor":0},"prices":{"memory":"577/10000","steps":"721/10000000"}}}}`)
+
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+		got, err := client.ProposedProtocolParameters(context.Background())
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		update, ok := got["637f2e950b0fd8f8e3e811c5fbeb19e411e7a2bf37272b84b29c1a0b"]
+		if !ok {
+			t.Fatalf("got %v; want proposal of genesis delegate", got)
+		}
+		if update.MaxBlockBodySize == nil || *update.MaxBlockBodySize != 90112 {
+			t.Fatalf("got %v; want 90112", update.MaxBlockBodySize)
+		}
+		if got, want := update.PoolInfluence.String(), "3/10"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := update.ProtocolVersion.Major, uint64(8); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := update.Prices.Steps.String(), "721/10000000"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if update.MaxTxSize != nil {
+			t.Fatalf("got %v; want nil", *update.MaxTxSize)
+		}
+		if len(update.Raw) == 0 {
+			t.Fatalf("got empty raw; want proposal json")
+		}
+	})
+}
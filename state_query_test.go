@@ -17,10 +17,16 @@ package ogmigo
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/statequery"
 )
 
 func TestClient_ChainTip(t *testing.T) {
@@ -138,3 +144,426 @@ func TestClient_UtxosByAddress(t *testing.T) {
 	encoder.SetIndent("", "  ")
 	_ = encoder.Encode(utxos)
 }
+
+func TestSlotsBetween(t *testing.T) {
+	if got, want := SlotsBetween(100, 250), uint64(150); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := SlotsBetween(250, 100), uint64(150); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	// across the byron to shelley boundary
+	var history EraHistory
+	if err := json.Unmarshal([]byte(testEraSummaries), &history.Summaries); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	byron, _, _, err := history.EpochSlots(207)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	_, shelley, _, err := history.EpochSlots(208)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := SlotsBetween(byron, shelley), uint64(21600+432000-1); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestApproxBlocks(t *testing.T) {
+	if got, want := ApproxBlocks(432000, 0.05), uint64(21600); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := ApproxBlocks(0, 0.05), uint64(0); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestClient_AllGenesisConfigs(t *testing.T) {
+	endpoint, requests := queryServer(t, `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"Query","result":{"networkMagic":764824073}}`)
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	configs, err := client.AllGenesisConfigs(context.Background())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(configs), len(GenesisEras); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	for _, era := range GenesisEras {
+		if got, want := string(configs[era]), `{"networkMagic":764824073}`; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+
+		var request struct {
+			Args struct {
+				Query struct {
+					GenesisConfig string
+				}
+			}
+		}
+		if err := json.Unmarshal(<-requests, &request); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, want := request.Args.Query.GenesisConfig, era; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	}
+}
+
+func TestClient_CachedEraHistory(t *testing.T) {
+	endpoint, requests := queryServer(t, `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"Query","result":[{"start":{"time":0,"slot":0,"epoch":0},"end":{"time":89856000,"slot":4492800,"epoch":208},"parameters":{"epochLength":21600,"slotLength":20,"safeZone":4320}}]}`)
+
+	var (
+		ctx    = context.Background()
+		client = New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	)
+	for i := 0; i < 3; i++ {
+		history, err := client.CachedEraHistory(ctx, time.Minute)
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, want := len(history.Summaries), 1; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	}
+	if got, want := len(requests), 1; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	// an expired ttl queries ogmios again
+	if _, err := client.CachedEraHistory(ctx, 0); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(requests), 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+const testEraSummaries = `[{"start":{"time":0,"slot":0,"epoch":0},"end":{"time":89856000,"slot":4492800,"epoch":208},"parameters":{"epochLength":21600,"slotLength":20,"safeZone":4320}},{"start":{"time":89856000,"slot":4492800,"epoch":208},"end":{"time":101952000,"slot":16588800,"epoch":236},"parameters":{"epochLength":432000,"slotLength":1,"safeZone":129600}}]`
+
+func TestEraHistory_EpochSlots(t *testing.T) {
+	var history EraHistory
+	if err := json.Unmarshal([]byte(testEraSummaries), &history.Summaries); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	tests := map[string]struct {
+		Epoch      uint64
+		First      uint64
+		Last       uint64
+		SlotLength time.Duration
+	}{
+		"byron": {
+			Epoch:      1,
+			First:      21600,
+			Last:       43199,
+			SlotLength: 20 * time.Second,
+		},
+		"first shelley": {
+			Epoch:      208,
+			First:      4492800,
+			Last:       4924799,
+			SlotLength: time.Second,
+		},
+		"beyond final summary": {
+			Epoch:      240,
+			First:      18316800,
+			Last:       18748799,
+			SlotLength: time.Second,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			first, last, slotLength, err := history.EpochSlots(tc.Epoch)
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if first != tc.First || last != tc.Last || slotLength != tc.SlotLength {
+				t.Fatalf("got %v, %v, %v; want %v, %v, %v", first, last, slotLength, tc.First, tc.Last, tc.SlotLength)
+			}
+		})
+	}
+
+	if _, _, _, err := (&EraHistory{}).EpochSlots(1); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func TestClient_EpochDetails(t *testing.T) {
+	endpoint := routedQueryServer(t, map[string]string{
+		"currentEpoch": `209`,
+		"ledgerTip":    `{"slot":5356000,"hash":"abc"}`,
+		"eraSummaries": testEraSummaries,
+	})
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	details, err := client.EpochDetails(context.Background())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	want := EpochDetails{
+		Epoch:         209,
+		FirstSlot:     4924800,
+		LastSlot:      5356799,
+		TimeRemaining: 800 * time.Second,
+	}
+	if details != want {
+		t.Fatalf("got %#v; want %#v", details, want)
+	}
+}
+
+func TestClient_WaitUntilSynced(t *testing.T) {
+	interval := syncPollInterval
+	syncPollInterval = time.Millisecond
+	defer func() { syncPollInterval = interval }()
+
+	endpoint := routedQueryServer(t, map[string]string{
+		"ledgerTip": `{"slot":1000,"hash":"abc"}`,
+		"chainTip":  `{"slot":1100,"hash":"def"}`,
+	})
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+
+	t.Run("within tolerance", func(t *testing.T) {
+		if err := client.WaitUntilSynced(context.Background(), 100); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+
+	t.Run("behind", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := client.WaitUntilSynced(ctx, 99)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got %v; want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestClient_UtxosByAddressWithAsset(t *testing.T) {
+	endpoint, requests := queryServer(t, `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"Query","result":[`+
+		`[{"txId":"a","index":0},{"address":"addr1","value":{"coins":2000000,"assets":{"abc.6e6674":1}}}],`+
+		`[{"txId":"b","index":1},{"address":"addr1","value":{"coins":5000000}}],`+
+		`[{"txId":"c","index":2},{"address":"addr2","value":{"coins":2000000,"assets":{"abc.6f74686572":1}}}]]}`)
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	utxos, err := client.UtxosByAddressWithAsset(context.Background(), []string{"addr1", "addr2"}, "abc", "6e6674")
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(utxos), 1; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := utxos[0].TxIn.TxHash, "a"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	var request struct {
+		Args struct {
+			Query struct {
+				Utxo []string
+			}
+		}
+	}
+	if err := json.Unmarshal(<-requests, &request); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(request.Args.Query.Utxo), 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func TestClient_GenesisInitialFunds(t *testing.T) {
+	endpoint := routedQueryServer(t, map[string]string{
+		`{"genesisConfig":"byron"}`:   `{"networkMagic":42,"initialFunds":{"2cWKMJemoBai":1000000,"both":5}}`,
+		`{"genesisConfig":"shelley"}`: `{"networkMagic":42,"initialFunds":{"addr_test1vz":9223372036854775808,"both":7}}`,
+	})
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	funds, err := client.GenesisInitialFunds(context.Background())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	want := map[string]string{
+		"2cWKMJemoBai": "1000000",
+		"addr_test1vz": "9223372036854775808",
+		"both":         "12",
+	}
+	if got, want := len(funds), len(want); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	for address, coins := range want {
+		if got := funds[address].Coins.String(); got != coins {
+			t.Fatalf("got %v; want %v", got, coins)
+		}
+	}
+}
+
+func TestClient_UtxoSnapshot(t *testing.T) {
+	pageSize := utxoSnapshotPageSize
+	utxoSnapshotPageSize = 2
+	defer func() { utxoSnapshotPageSize = pageSize }()
+
+	point := chainsync.PointStruct{Hash: "abc", Slot: 1000}.Point()
+
+	t.Run("ok", func(t *testing.T) {
+		endpoint, methods := methodServer(t, map[string]string{
+			"Acquire": `{"AcquireSuccess":{"point":{"slot":1000,"hash":"abc"}}}`,
+			"Query": `[` +
+				`[{"txId":"a","index":0},{"address":"addr1","value":{"coins":1}}],` +
+				`[{"txId":"b","index":0},{"address":"addr1","value":{"coins":2}}],` +
+				`[{"txId":"c","index":0},{"address":"addr1","value":{"coins":3}}]]`,
+			"Release": `"Released"`,
+		})
+
+		var pages [][]statequery.Utxo
+		callback := func(utxos []statequery.Utxo) error {
+			pages = append(pages, utxos)
+			return nil
+		}
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+		if err := client.UtxoSnapshot(context.Background(), point, callback, "addr1"); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, want := len(pages), 2; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := len(pages[0])+len(pages[1]), 3; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := pages[1][0].TxIn.TxHash, "c"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		for _, want := range []string{"Acquire", "Query", "Release"} {
+			if got := <-methods; got != want {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+		}
+	})
+
+	t.Run("acquire failure", func(t *testing.T) {
+		endpoint, _ := methodServer(t, map[string]string{
+			"Acquire": `{"AcquireFailure":{"failure":"pointTooOld"}}`,
+		})
+
+		callback := func([]statequery.Utxo) error {
+			t.Fatalf("got callback; want none")
+			return nil
+		}
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+		err := client.UtxoSnapshot(context.Background(), point, callback)
+		if got, want := fmt.Sprint(err), "pointTooOld"; !strings.Contains(got, want) {
+			t.Fatalf("got %v; want contains %v", got, want)
+		}
+	})
+}
+
+func TestClient_UtxosByAddressNetwork(t *testing.T) {
+	endpoint, _ := queryServer(t, `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"Query","result":[`+
+		`[{"txId":"a","index":0},{"address":"addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz","value":{"coins":1}}]]}`)
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	if _, err := client.UtxosByAddress(context.Background(), "addr_test1"); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	client = New(WithEndpoint(endpoint), WithLogger(NopLogger), WithNetwork(chainsync.NetworkMainnet))
+	_, err := client.UtxosByAddress(context.Background(), "addr_test1")
+	if !errors.Is(err, chainsync.ErrNetworkMismatch) {
+		t.Fatalf("got %v; want %v", err, chainsync.ErrNetworkMismatch)
+	}
+}
+
+func TestClient_StakePools(t *testing.T) {
+	const pool1 = `{"id":"pool1","vrf":"v1","pledge":100000000,"cost":340000000,"margin":"1/20","rewardAccount":"stake1u","owners":["o1"],"relays":[{"hostname":"relay.example.com","port":3001}],"metadata":{"url":"https://example.com/pool.json","hash":"h1"}}`
+	const pool2 = `{"id":"pool2","vrf":"v2","pledge":0,"cost":340000000,"margin":"0/1","rewardAccount":"stake1v","owners":[],"relays":[],"metadata":null}`
+
+	endpoint := routedQueryServer(t, map[string]string{
+		"poolIds":                              `["pool1","pool2"]`,
+		`{"poolParameters":["pool1","pool2"]}`: `{"pool1":` + pool1 + `,"pool2":` + pool2 + `}`,
+		`{"poolParameters":["pool2"]}`:         `{"pool2":` + pool2 + `}`,
+	})
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+
+	t.Run("all", func(t *testing.T) {
+		pools, err := client.StakePools(context.Background())
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if got, want := len(pools), 2; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		pool := pools["pool1"]
+		if got, want := pool.Cost.Int64(), int64(340000000); got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := pool.Margin.String(), "1/20"; got != want {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if got, want := pool.Relays[0].HostPorts(), []string{"relay.example.com:3001"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+		if pools["pool2"].Metadata != nil {
+			t.Fatalf("got %v; want nil", pools["pool2"].Metadata)
+		}
+	})
+
+	t.Run("by id", func(t *testing.T) {
+		pools, err := client.StakePools(context.Background(), "pool2")
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+		if _, ok := pools["pool2"]; !ok || len(pools) != 1 {
+			t.Fatalf("got %v; want pool2", pools)
+		}
+	})
+}
+
+func TestClient_NetworkBlockHeight(t *testing.T) {
+	tests := map[string]struct {
+		Result  string
+		Height  uint64
+		OK      bool
+		WantErr bool
+	}{
+		"height": {
+			Result: `8123456`,
+			Height: 8123456,
+			OK:     true,
+		},
+		"origin": {
+			Result: `"origin"`,
+		},
+		"invalid": {
+			Result:  `"abc"`,
+			WantErr: true,
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			endpoint := routedQueryServer(t, map[string]string{"blockHeight": tc.Result})
+			client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+
+			height, ok, err := client.NetworkBlockHeight(context.Background())
+			if tc.WantErr {
+				if err == nil {
+					t.Fatalf("got nil; want err")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if height != tc.Height || ok != tc.OK {
+				t.Fatalf("got %v, %v; want %v, %v", height, ok, tc.Height, tc.OK)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 Matt Ho
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
+)
+
+type Store struct {
+	client     redis.UniversalClient
+	key        string
+	maxHistory int
+}
+
+// New returns a Store that keeps the most recent maxHistory points in a redis list
+func New(client redis.UniversalClient, prefix string, maxHistory int) *Store {
+	if maxHistory <= 0 {
+		maxHistory = 10
+	}
+	return &Store{
+		client:     client,
+		key:        strings.TrimRight(prefix, "/") + "/points",
+		maxHistory: maxHistory,
+	}
+}
+
+// Save the point; save will be called multiple times and should only
+// keep track of the most recent points
+func (s *Store) Save(ctx context.Context, point chainsync.Point) error {
+	data, err := json.Marshal(point)
+	if err != nil {
+		return fmt.Errorf("failed to save point: %w", err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, s.key, data)
+		pipe.LTrim(ctx, s.key, 0, int64(s.maxHistory-1))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save point: %w", err)
+	}
+
+	return nil
+}
+
+// Load saved points, newest first
+func (s *Store) Load(ctx context.Context) (chainsync.Points, error) {
+	values, err := s.client.LRange(ctx, s.key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load points: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	var pp chainsync.Points
+	for _, value := range values {
+		var p chainsync.Point
+		if err := json.Unmarshal([]byte(value), &p); err != nil {
+			return nil, fmt.Errorf("failed to load points: %w", err)
+		}
+		pp = append(pp, p)
+	}
+
+	sort.Sort(pp)
+
+	return pp, nil
+}
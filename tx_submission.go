@@ -17,6 +17,7 @@ package ogmigo
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,6 +25,10 @@ import (
 	"strings"
 
 	"github.com/buger/jsonparser"
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
 )
 
 type Response struct {
@@ -35,17 +40,37 @@ type Response struct {
 	Result      json.RawMessage
 }
 
-// SubmitTx submits the transaction via ogmios
+// SubmitTx submits the transaction, given either as a json envelope containing cborHex, as
+// written by cardano-cli, or as bare hex, via ogmios
 // https://ogmios.dev/mini-protocols/local-tx-submission/
 func (c *Client) SubmitTx(ctx context.Context, data []byte) (err error) {
-	var content struct{ CborHex string }
-	if err := json.Unmarshal(data, &content); err != nil {
-		return fmt.Errorf("failed to decode signed tx: %w", err)
+	signedTx := string(bytes.TrimSpace(data))
+	if strings.HasPrefix(signedTx, "{") {
+		var content struct{ CborHex string }
+		if err := json.Unmarshal(data, &content); err != nil {
+			return fmt.Errorf("failed to decode signed tx: %w", err)
+		}
+		if content.CborHex != "" {
+			signedTx = content.CborHex
+		}
 	}
 
-	signedTx := content.CborHex
-	if signedTx == "" {
-		signedTx = string(data)
+	return c.submitTx(ctx, signedTx)
+}
+
+// SubmitTxBytes submits the raw CBOR encoded transaction via ogmios
+func (c *Client) SubmitTxBytes(ctx context.Context, data []byte) error {
+	return c.submitTx(ctx, hex.EncodeToString(data))
+}
+
+func (c *Client) submitTx(ctx context.Context, signedTx string) error {
+	var want string
+	if c.options.validateTxID {
+		id, err := chainsync.ComputeTxID(signedTx)
+		if err != nil {
+			return fmt.Errorf("failed to submit tx: %w", err)
+		}
+		want = id.String()
 	}
 
 	var (
@@ -56,7 +81,416 @@ func (c *Client) SubmitTx(ctx context.Context, data []byte) (err error) {
 		return fmt.Errorf("failed to submit tx: %w", err)
 	}
 
-	return readSubmitTx(raw)
+	if err := readSubmitTx(raw); err != nil {
+		return err
+	}
+
+	if want != "" {
+		// older versions of ogmios do not return the id of the submitted tx
+		if got, err := jsonparser.GetString(raw, "result", "SubmitSuccess", "txId"); err == nil && got != want {
+			return fmt.Errorf("SubmitTx failed: ogmios returned tx id, %v; want %v", got, want)
+		}
+	}
+
+	return nil
+}
+
+// ExUnits captures the execution budget of a script
+type ExUnits struct {
+	Memory uint64 `json:"memory"`
+	Steps  uint64 `json:"steps"`
+}
+
+// EvaluateTx evaluates the execution units of the scripts in the hex encoded transaction; results
+// are keyed by redeemer pointer e.g. spend:0
+// https://ogmios.dev/mini-protocols/local-tx-submission/#evaluatetx
+func (c *Client) EvaluateTx(ctx context.Context, cborHex string) (map[string]ExUnits, error) {
+	var (
+		payload = makePayload("EvaluateTx", Map{"evaluate": cborHex})
+		content struct {
+			Result struct {
+				EvaluationResult  map[string]ExUnits
+				EvaluationFailure json.RawMessage
+			}
+		}
+	)
+	if err := c.query(ctx, payload, &content); err != nil {
+		return nil, fmt.Errorf("failed to evaluate tx: %w", err)
+	}
+
+	if failure := content.Result.EvaluationFailure; len(failure) > 0 {
+		return nil, EvaluateTxError{message: failure}
+	}
+
+	return content.Result.EvaluationResult, nil
+}
+
+// redeemerTags maps the redeemer tags used by the ledger to the names used by ogmios
+var redeemerTags = map[uint64]string{
+	0: "spend",
+	1: "mint",
+	2: "certificate",
+	3: "withdrawal",
+}
+
+type redeemer struct {
+	_       struct{} `cbor:",toarray"`
+	Tag     uint64
+	Index   uint64
+	Data    cbor.RawMessage
+	ExUnits [2]uint64
+}
+
+// CostModels holds the cost model parameters of the plutus languages used by a tx keyed by
+// language, plutus:v1 or plutus:v2, with the parameters in ledger order
+type CostModels map[string][]int64
+
+// PatchExUnits replaces the execution units of the redeemers in the hex encoded, unsigned
+// transaction with budgets, as returned by EvaluateTx, keyed by redeemer pointer e.g. spend:0.
+// As the script integrity hash of the body commits to the redeemers, it is recomputed using
+// costModels, which must hold exactly the languages used by the scripts of the tx.  Patching
+// changes the tx id, so the tx must be signed afterwards; existing vkey witnesses are invalidated.
+// SubmitWithBudgets patches, signs and submits in one step.
+func PatchExUnits(cborHex string, budgets map[string]ExUnits, costModels CostModels) (string, error) {
+	data, err := hex.DecodeString(cborHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to patch ex units: invalid hex: %w", err)
+	}
+
+	var items []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &items); err != nil {
+		return "", fmt.Errorf("failed to patch ex units: invalid cbor: %w", err)
+	}
+	if len(items) < 2 {
+		return "", fmt.Errorf("failed to patch ex units: missing witness set")
+	}
+
+	var body map[uint64]cbor.RawMessage
+	if err := cbor.Unmarshal(items[0], &body); err != nil {
+		return "", fmt.Errorf("failed to patch ex units: invalid body: %w", err)
+	}
+	var witnesses map[uint64]cbor.RawMessage
+	if err := cbor.Unmarshal(items[1], &witnesses); err != nil {
+		return "", fmt.Errorf("failed to patch ex units: invalid witness set: %w", err)
+	}
+	var redeemers []redeemer
+	if raw, ok := witnesses[5]; ok {
+		if err := cbor.Unmarshal(raw, &redeemers); err != nil {
+			return "", fmt.Errorf("failed to patch ex units: invalid redeemers: %w", err)
+		}
+	}
+
+	patched := map[string]bool{}
+	for i, r := range redeemers {
+		pointer := fmt.Sprintf("%v:%v", redeemerTags[r.Tag], r.Index)
+		if budget, ok := budgets[pointer]; ok {
+			redeemers[i].ExUnits = [2]uint64{budget.Memory, budget.Steps}
+			patched[pointer] = true
+		}
+	}
+	for pointer := range budgets {
+		if !patched[pointer] {
+			return "", fmt.Errorf("failed to patch ex units: no redeemer for %v", pointer)
+		}
+	}
+	if len(redeemers) == 0 {
+		return cborHex, nil
+	}
+
+	em, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		return "", fmt.Errorf("failed to patch ex units: %w", err)
+	}
+	if witnesses[5], err = em.Marshal(redeemers); err != nil {
+		return "", fmt.Errorf("failed to patch ex units: failed to encode redeemers: %w", err)
+	}
+	hash, err := scriptIntegrityHash(em, witnesses[5], witnesses[4], costModels)
+	if err != nil {
+		return "", fmt.Errorf("failed to patch ex units: %w", err)
+	}
+	if body[11], err = em.Marshal(hash); err != nil {
+		return "", fmt.Errorf("failed to patch ex units: failed to encode script integrity hash: %w", err)
+	}
+	if items[0], err = em.Marshal(body); err != nil {
+		return "", fmt.Errorf("failed to patch ex units: failed to encode body: %w", err)
+	}
+	if items[1], err = em.Marshal(witnesses); err != nil {
+		return "", fmt.Errorf("failed to patch ex units: failed to encode witness set: %w", err)
+	}
+	tx, err := em.Marshal(items)
+	if err != nil {
+		return "", fmt.Errorf("failed to patch ex units: failed to encode tx: %w", err)
+	}
+	return hex.EncodeToString(tx), nil
+}
+
+// SignFunc returns the cbor encoded vkey witnesses, a list of [vkey, signature], for the cbor
+// encoded tx body
+type SignFunc func(body []byte) (witnesses []byte, err error)
+
+// SubmitWithBudgets patches the execution units of the redeemers in the hex encoded transaction
+// with budgets, as returned by EvaluateTx, signs the patched body with sign and submits it,
+// returning the id of the tx.  The script integrity hash is recomputed from the cost models in
+// the current protocol parameters for the languages of the scripts the tx carries or references
+// via its inputs; any vkey witnesses in the tx are replaced by those returned by sign.
+func (c *Client) SubmitWithBudgets(ctx context.Context, cborHex string, budgets map[string]ExUnits, sign SignFunc) (chainsync.TxID, error) {
+	costModels, err := c.txCostModels(ctx, cborHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit tx: %w", err)
+	}
+	patched, err := PatchExUnits(cborHex, budgets, costModels)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit tx: %w", err)
+	}
+	signed, err := signTx(patched, sign)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit tx: %w", err)
+	}
+	id, err := chainsync.ComputeTxID(signed)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit tx: %w", err)
+	}
+	if err := c.submitTx(ctx, signed); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// txCostModels returns the cost models, from the current protocol parameters, of the plutus
+// languages used by the scripts in the witness set of the tx or referenced by its inputs
+func (c *Client) txCostModels(ctx context.Context, cborHex string) (CostModels, error) {
+	data, err := hex.DecodeString(cborHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex: %w", err)
+	}
+	var items []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid cbor: %w", err)
+	}
+	if len(items) < 2 {
+		return nil, fmt.Errorf("missing witness set")
+	}
+
+	type txIn struct {
+		_     struct{} `cbor:",toarray"`
+		TxID  []byte
+		Index int
+	}
+	var body struct {
+		Inputs          []txIn `cbor:"0,keyasint"`
+		ReferenceInputs []txIn `cbor:"18,keyasint"`
+	}
+	if err := cbor.Unmarshal(items[0], &body); err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+	var witnesses map[uint64]cbor.RawMessage
+	if err := cbor.Unmarshal(items[1], &witnesses); err != nil {
+		return nil, fmt.Errorf("invalid witness set: %w", err)
+	}
+
+	languages := map[string]bool{}
+	if _, ok := witnesses[3]; ok {
+		languages["plutus:v1"] = true
+	}
+	if _, ok := witnesses[6]; ok {
+		languages["plutus:v2"] = true
+	}
+
+	var txIns []chainsync.TxIn
+	for _, in := range append(body.Inputs, body.ReferenceInputs...) {
+		txIns = append(txIns, chainsync.TxIn{TxHash: hex.EncodeToString(in.TxID), Index: in.Index})
+	}
+	if len(txIns) > 0 {
+		utxos, err := c.UtxosByTxIn(ctx, txIns...)
+		if err != nil {
+			return nil, err
+		}
+		for _, utxo := range utxos {
+			if len(utxo.TxOut.Script) == 0 {
+				continue
+			}
+			var script map[string]json.RawMessage
+			if err := json.Unmarshal(utxo.TxOut.Script, &script); err != nil {
+				return nil, fmt.Errorf("failed to decode reference script of %v: %w", utxo.TxIn, err)
+			}
+			for language := range script {
+				if strings.HasPrefix(language, "plutus:") {
+					languages[language] = true
+				}
+			}
+		}
+	}
+
+	raw, err := c.CurrentProtocolParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var params struct {
+		CostModels map[string]map[string]int64 `json:"costModels"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, fmt.Errorf("failed to decode protocol parameters: %w", err)
+	}
+
+	costModels := CostModels{}
+	for language := range languages {
+		model, ok := params.CostModels[language]
+		if !ok {
+			return nil, fmt.Errorf("no cost model for %v in protocol parameters", language)
+		}
+		costModels[language] = orderCostModel(model)
+	}
+	return costModels, nil
+}
+
+// orderCostModel lists the parameters of a cost model, as reported by ogmios v5, in ledger
+// order; the ledger orders the parameters of plutus:v1 and plutus:v2 by name
+func orderCostModel(model map[string]int64) []int64 {
+	names := make([]string, 0, len(model))
+	for name := range model {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]int64, 0, len(names))
+	for _, name := range names {
+		params = append(params, model[name])
+	}
+	return params
+}
+
+// signTx replaces the vkey witnesses of the hex encoded tx with those returned by sign for
+// its body
+func signTx(cborHex string, sign SignFunc) (string, error) {
+	data, err := hex.DecodeString(cborHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign tx: invalid hex: %w", err)
+	}
+	var items []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &items); err != nil {
+		return "", fmt.Errorf("failed to sign tx: invalid cbor: %w", err)
+	}
+	if len(items) < 2 {
+		return "", fmt.Errorf("failed to sign tx: missing witness set")
+	}
+	var witnesses map[uint64]cbor.RawMessage
+	if err := cbor.Unmarshal(items[1], &witnesses); err != nil {
+		return "", fmt.Errorf("failed to sign tx: invalid witness set: %w", err)
+	}
+
+	vkeys, err := sign(items[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign tx: %w", err)
+	}
+	if err := cbor.Valid(vkeys); err != nil {
+		return "", fmt.Errorf("failed to sign tx: invalid vkey witnesses: %w", err)
+	}
+	witnesses[0] = vkeys
+
+	em, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		return "", fmt.Errorf("failed to sign tx: %w", err)
+	}
+	if items[1], err = em.Marshal(witnesses); err != nil {
+		return "", fmt.Errorf("failed to sign tx: failed to encode witness set: %w", err)
+	}
+	tx, err := em.Marshal(items)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign tx: failed to encode tx: %w", err)
+	}
+	return hex.EncodeToString(tx), nil
+}
+
+// scriptIntegrityHash returns the blake2b-256 hash of the encoded redeemers, datums, which may
+// be empty, and language views of the cost models
+func scriptIntegrityHash(em cbor.EncMode, redeemers, datums []byte, costModels CostModels) ([]byte, error) {
+	views, err := languageViews(em, costModels)
+	if err != nil {
+		return nil, err
+	}
+
+	var preimage []byte
+	preimage = append(preimage, redeemers...)
+	preimage = append(preimage, datums...)
+	preimage = append(preimage, views...)
+	hash := blake2b.Sum256(preimage)
+	return hash[:], nil
+}
+
+// languageViews encodes the cost models as the ledger does when hashing script data; for
+// historical reasons plutus:v1 wraps both its key and its indefinite length parameter list
+// in byte strings
+func languageViews(em cbor.EncMode, costModels CostModels) ([]byte, error) {
+	type view struct{ key, value []byte }
+
+	var views []view
+	for language, params := range costModels {
+		switch language {
+		case "plutus:v1":
+			value := []byte{0x9f}
+			for _, param := range params {
+				data, err := em.Marshal(param)
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode %v cost model: %w", language, err)
+				}
+				value = append(value, data...)
+			}
+			value = append(value, 0xff)
+
+			key, _ := em.Marshal([]byte{0x00})
+			data, err := em.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %v cost model: %w", language, err)
+			}
+			views = append(views, view{key: key, value: data})
+
+		case "plutus:v2":
+			key, _ := em.Marshal(1)
+			data, err := em.Marshal(params)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %v cost model: %w", language, err)
+			}
+			views = append(views, view{key: key, value: data})
+
+		default:
+			return nil, fmt.Errorf("unsupported plutus language, %v", language)
+		}
+	}
+
+	// canonical cbor orders map keys by length and then bytewise
+	sort.Slice(views, func(i, j int) bool {
+		if a, b := views[i].key, views[j].key; len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return bytes.Compare(views[i].key, views[j].key) < 0
+	})
+
+	data := []byte{0xa0 | byte(len(views))} // map header; at most two languages
+	for _, v := range views {
+		data = append(data, v.key...)
+		data = append(data, v.value...)
+	}
+	return data, nil
+}
+
+// EvaluateTxBytes evaluates the execution units of the scripts in the raw CBOR encoded transaction
+func (c *Client) EvaluateTxBytes(ctx context.Context, data []byte) (map[string]ExUnits, error) {
+	return c.EvaluateTx(ctx, hex.EncodeToString(data))
+}
+
+// EvaluateTxError encapsulates the EvaluationFailure returned by EvaluateTx
+type EvaluateTxError struct {
+	message json.RawMessage
+}
+
+// Message returns the raw EvaluationFailure
+func (e EvaluateTxError) Message() json.RawMessage {
+	return e.message
+}
+
+// Error implements the error interface
+func (e EvaluateTxError) Error() string {
+	return fmt.Sprintf("EvaluateTx failed: %v", string(e.message))
 }
 
 // SubmitTxError encapsulates the SubmitTx errors and allows the results to be parsed
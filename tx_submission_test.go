@@ -16,12 +16,21 @@ package ogmigo
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/buger/jsonparser"
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/SundaeSwap-finance/ogmigo/ouroboros/chainsync"
 )
 
 func TestClient_SubmitTx(t *testing.T) {
@@ -38,6 +47,117 @@ func TestClient_SubmitTx(t *testing.T) {
 	}
 }
 
+func TestClient_SubmitTxBytes(t *testing.T) {
+	endpoint, requests := queryServer(t, `{"type":"jsonwsp/response","methodname":"SubmitTx","result":"SubmitSuccess"}`)
+
+	ctx := context.Background()
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	if err := client.SubmitTxBytes(ctx, []byte{0x84, 0xa4}); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	if got, want := string(<-requests), `"args":{"submit":"84a4"}`; !strings.Contains(got, want) {
+		t.Fatalf("got %v; want contains %v", got, want)
+	}
+}
+
+func TestClient_SubmitTxForms(t *testing.T) {
+	tests := map[string]struct {
+		Data string
+	}{
+		"envelope": {Data: `{"type":"Tx BabbageEra","cborHex":"84a4"}`},
+		"bare hex": {Data: "84a4\n"},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			endpoint, requests := queryServer(t, `{"type":"jsonwsp/response","methodname":"SubmitTx","result":"SubmitSuccess"}`)
+
+			client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+			if err := client.SubmitTx(context.Background(), []byte(tc.Data)); err != nil {
+				t.Fatalf("got %v; want nil", err)
+			}
+			if got, want := string(<-requests), `"args":{"submit":"84a4"}`; !strings.Contains(got, want) {
+				t.Fatalf("got %v; want contains %v", got, want)
+			}
+		})
+	}
+}
+
+func TestClient_SubmitTxValidateTxID(t *testing.T) {
+	body, err := cbor.Marshal(map[int]interface{}{2: 170000})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	tx, err := cbor.Marshal([]interface{}{cbor.RawMessage(body), map[int]interface{}{}, true, nil})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	hash := blake2b.Sum256(body)
+	txID := hex.EncodeToString(hash[:])
+
+	t.Run("match", func(t *testing.T) {
+		endpoint, _ := queryServer(t, `{"type":"jsonwsp/response","methodname":"SubmitTx","result":{"SubmitSuccess":{"txId":"`+txID+`"}}}`)
+
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithValidateTxID(true))
+		if err := client.SubmitTxBytes(context.Background(), tx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		endpoint, _ := queryServer(t, `{"type":"jsonwsp/response","methodname":"SubmitTx","result":{"SubmitSuccess":{"txId":"blah"}}}`)
+
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithValidateTxID(true))
+		if err := client.SubmitTxBytes(context.Background(), tx); err == nil {
+			t.Fatalf("got nil; want err")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		endpoint, _ := queryServer(t, `{"type":"jsonwsp/response","methodname":"SubmitTx","result":{"SubmitSuccess":{"txId":"blah"}}}`)
+
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+		if err := client.SubmitTxBytes(context.Background(), tx); err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+	})
+}
+
+func TestClient_EvaluateTxBytes(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		endpoint, requests := queryServer(t, `{"type":"jsonwsp/response","methodname":"EvaluateTx","result":{"EvaluationResult":{"spend:0":{"memory":1700,"steps":476468}}}}`)
+
+		ctx := context.Background()
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+		got, err := client.EvaluateTxBytes(ctx, []byte{0x84, 0xa4})
+		if err != nil {
+			t.Fatalf("got %v; want nil", err)
+		}
+
+		want := map[string]ExUnits{"spend:0": {Memory: 1700, Steps: 476468}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %#v; want %#v", got, want)
+		}
+		if got, want := string(<-requests), `"args":{"evaluate":"84a4"}`; !strings.Contains(got, want) {
+			t.Fatalf("got %v; want contains %v", got, want)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		endpoint, _ := queryServer(t, `{"type":"jsonwsp/response","methodname":"EvaluateTx","result":{"EvaluationFailure":{"CannotCreateEvaluationContext":{"reason":"blah"}}}}`)
+
+		ctx := context.Background()
+		client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+		_, err := client.EvaluateTxBytes(ctx, []byte{0x84, 0xa4})
+
+		var ete EvaluateTxError
+		if ok := errors.As(err, &ete); !ok {
+			t.Fatalf("got %v; want EvaluateTxError", err)
+		}
+	})
+}
+
 func TestSubmitTxResult(t *testing.T) {
 	err := filepath.Walk("ext/ogmios/server/test/vectors/TxSubmission", testSubmitTxResult(t))
 	if err != nil {
@@ -81,3 +201,198 @@ func testSubmitTxResult(t *testing.T) filepath.WalkFunc {
 		return nil
 	}
 }
+
+func testScriptTx(t *testing.T) string {
+	tx := []interface{}{
+		map[uint64]interface{}{2: 170000, 11: make([]byte, 32)},
+		map[uint64]interface{}{
+			4: []interface{}{[]byte{0x01}},
+			5: []interface{}{
+				[]interface{}{0, 0, 121, []uint64{1, 2}},
+				[]interface{}{1, 0, []byte{0xff}, []uint64{3, 4}},
+			},
+		},
+		true,
+		nil,
+	}
+	data, err := cbor.Marshal(tx)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	return hex.EncodeToString(data)
+}
+
+func TestPatchExUnits(t *testing.T) {
+	var (
+		tx         = testScriptTx(t)
+		budgets    = map[string]ExUnits{"spend:0": {Memory: 1700, Steps: 476468}}
+		costModels = CostModels{"plutus:v1": {4, 5}, "plutus:v2": {1, 2, 3}}
+	)
+
+	patched, err := PatchExUnits(tx, budgets, costModels)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	data, _ := hex.DecodeString(patched)
+	var decoded []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var witnesses struct {
+		Redeemers []redeemer `cbor:"5,keyasint"`
+	}
+	if err := cbor.Unmarshal(decoded[1], &witnesses); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := witnesses.Redeemers[0].ExUnits, [2]uint64{1700, 476468}; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := witnesses.Redeemers[1].ExUnits, [2]uint64{3, 4}; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	// redeemers || datums || language views, with plutus:v2 ordered first by its shorter key
+	preimage, _ := hex.DecodeString("" +
+		"82" + "84000018798219" + "06a41a00074534" + "84010041ff820304" +
+		"814101" +
+		"a2" + "0183010203" + "4100449f0405ff")
+	want := blake2b.Sum256(preimage)
+
+	var body struct {
+		Fee                 uint64 `cbor:"2,keyasint"`
+		ScriptIntegrityHash []byte `cbor:"11,keyasint"`
+	}
+	if err := cbor.Unmarshal(decoded[0], &body); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got := body.ScriptIntegrityHash; !reflect.DeepEqual(got, want[:]) {
+		t.Fatalf("got %x; want %x", got, want)
+	}
+	if got, want := body.Fee, uint64(170000); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	// the body, and therefore the tx id, changes so the tx must be signed after patching
+	before, _ := chainsync.ComputeTxID(tx)
+	after, _ := chainsync.ComputeTxID(patched)
+	if before == after {
+		t.Fatalf("got %v; want tx id to change", after)
+	}
+
+	if _, err := PatchExUnits(tx, map[string]ExUnits{"withdrawal:0": {}}, costModels); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+	if _, err := PatchExUnits(tx, budgets, CostModels{"plutus:v3": nil}); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func TestClient_SubmitWithBudgets(t *testing.T) {
+	var (
+		budgets = map[string]ExUnits{"spend:0": {Memory: 1700, Steps: 476468}}
+		vkeys   = []interface{}{[]interface{}{make([]byte, 32), make([]byte, 64)}}
+	)
+	data, err := cbor.Marshal([]interface{}{
+		map[uint64]interface{}{2: 170000, 11: make([]byte, 32)},
+		map[uint64]interface{}{
+			5: []interface{}{[]interface{}{0, 0, 121, []uint64{1, 2}}},
+			6: []interface{}{[]byte{0x4e, 0x4d, 0x01}},
+		},
+		true,
+		nil,
+	})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	tx := hex.EncodeToString(data)
+
+	endpoint, _ := methodServer(t, map[string]string{
+		"Query":    `{"costModels":{"plutus:v1":{"a":9},"plutus:v2":{"b":2,"a":1}}}`,
+		"SubmitTx": `"SubmitSuccess"`,
+	})
+
+	var submitted string
+	tap := func(direction string, frame []byte) {
+		if direction == FrameOutbound && strings.Contains(string(frame), "SubmitTx") {
+			submitted, _ = jsonparser.GetString(frame, "args", "submit")
+		}
+	}
+
+	var signed []byte
+	sign := func(body []byte) ([]byte, error) {
+		signed = body
+		return cbor.Marshal(vkeys)
+	}
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithFrameTap(tap))
+	id, err := client.SubmitWithBudgets(context.Background(), tx, budgets, sign)
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	// plutus:v2 is the only language used by the tx, with its parameters ordered by name
+	patched, err := PatchExUnits(tx, budgets, CostModels{"plutus:v2": {1, 2}})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	data, _ = hex.DecodeString(patched)
+	var items []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &items); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := signed, []byte(items[0]); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %x; want %x", got, want)
+	}
+
+	if want, _ := chainsync.ComputeTxID(submitted); id != want {
+		t.Fatalf("got %v; want %v", id, want)
+	}
+	data, _ = hex.DecodeString(submitted)
+	var got []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	var witnesses struct {
+		VKeys [][]cbor.RawMessage `cbor:"0,keyasint"`
+	}
+	if err := cbor.Unmarshal(got[1], &witnesses); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := len(witnesses.VKeys), 1; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	failing := func([]byte) ([]byte, error) { return nil, errors.New("boom") }
+	if _, err := client.SubmitWithBudgets(context.Background(), tx, budgets, failing); err == nil {
+		t.Fatalf("got nil; want err")
+	}
+}
+
+func TestClient_txCostModels(t *testing.T) {
+	data, err := cbor.Marshal([]interface{}{
+		map[uint64]interface{}{
+			0:  []interface{}{[]interface{}{[]byte{0xaa}, 0}},
+			18: []interface{}{[]interface{}{[]byte{0xbb}, 1}},
+		},
+		map[uint64]interface{}{},
+		true,
+		nil,
+	})
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+
+	endpoint := routedQueryServer(t, map[string]string{
+		"currentProtocolParameters":                                  `{"costModels":{"plutus:v1":{"b":2,"a":1},"plutus:v2":{"a":3}}}`,
+		`{"utxo":[{"txId":"aa","index":0},{"txId":"bb","index":1}]}`: `[[{"txId":"aa","index":0},{"address":"addr","value":{"coins":1}}],[{"txId":"bb","index":1},{"address":"addr","value":{"coins":1},"script":{"plutus:v1":"4e4d01"}}]]`,
+	})
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	got, err := client.txCostModels(context.Background(), hex.EncodeToString(data))
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if want := (CostModels{"plutus:v1": {1, 2}}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
@@ -19,36 +19,90 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync/atomic"
 
 	"github.com/gorilla/websocket"
 )
 
-var fault = []byte(`jsonwsp/fault`)
+var (
+	fault     = []byte(`jsonwsp/fault`)
+	requestID uint64
+)
+
+// query submits the payload to ogmios and decodes the response into v; errors are
+// annotated with the method and request id to make them traceable
+func (c *Client) query(ctx context.Context, payload interface{}, v interface{}) error {
+	method := c.mirror(payload)
+	if err := c.options.limiter.wait(ctx); err != nil {
+		return fmt.Errorf("%v: %w", method, err)
+	}
+	if err := c.doQuery(ctx, payload, v); err != nil {
+		return fmt.Errorf("%v: %w", method, err)
+	}
+	return nil
+}
+
+// mirror assigns a request id to the payload and returns a description of the request
+// suitable for annotating errors
+func (c *Client) mirror(payload interface{}) string {
+	m, ok := payload.(Map)
+	if !ok {
+		return "unknown"
+	}
+	id := c.options.idGenerator()
+	m["mirror"] = Map{"id": id}
+	return fmt.Sprintf("%v (id=%s)", methodName(m), id)
+}
+
+// nextRequestID returns the next value of an incrementing counter shared by all clients
+func nextRequestID() json.RawMessage {
+	return json.RawMessage(strconv.FormatUint(atomic.AddUint64(&requestID, 1), 10))
+}
+
+// methodName returns a description of the ogmios method invoked by the payload e.g. Query/utxo
+func methodName(payload Map) string {
+	name, _ := payload["methodname"].(string)
+	args, _ := payload["args"].(Map)
+	switch query := args["query"].(type) {
+	case string:
+		return name + "/" + query
+	case Map:
+		for key := range query {
+			return name + "/" + key
+		}
+	}
+	return name
+}
+
+func (c *Client) doQuery(ctx context.Context, payload interface{}, v interface{}) error {
+	return c.withConn(ctx, func(conn *websocket.Conn) error {
+		return c.exchange(conn, payload, v)
+	})
+}
 
-func (c *Client) query(ctx context.Context, payload interface{}, v interface{}) (err error) {
+// withConn dials ogmios and invokes fn with the connection, closing the connection once fn
+// returns or ctx is canceled
+func (c *Client) withConn(ctx context.Context, fn func(conn *websocket.Conn) error) (err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	conn, _, err := c.options.websocketDialer().DialContext(ctx, c.options.endpoint, nil)
+	if err != nil {
+		return &UnreachableError{Endpoint: c.options.endpoint, Err: err}
+	}
+
 	var (
 		ch     = make(chan error, 1)
-		conn   *websocket.Conn
 		closed int64 // ensures close is only called once
 	)
 	go func() {
 		<-ctx.Done()
-		if conn != nil {
-			ch <- ctx.Err()
-			if v := atomic.AddInt64(&closed, 1); v == 1 {
-				conn.Close()
-			}
+		if v := atomic.AddInt64(&closed, 1); v == 1 {
+			ch <- ctx.Err() // unblocks an in-flight read or write
+			conn.Close()
 		}
 	}()
-
-	conn, _, err = websocket.DefaultDialer.DialContext(ctx, c.options.endpoint, nil)
-	if err != nil {
-		return fmt.Errorf("failed to connect to ogmios, %v: %w", c.options.endpoint, err)
-	}
 	defer func() {
 		if v := atomic.AddInt64(&closed, 1); v == 1 {
 			conn.Close()
@@ -57,14 +111,28 @@ func (c *Client) query(ctx context.Context, payload interface{}, v interface{})
 		}
 	}()
 
-	if err := conn.WriteJSON(payload); err != nil {
+	return fn(conn)
+}
+
+// exchange writes the payload to conn and decodes the response into v
+func (c *Client) exchange(conn *websocket.Conn, payload interface{}, v interface{}) error {
+	request, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	c.options.tap(FrameOutbound, request)
+	if err := conn.WriteMessage(websocket.TextMessage, request); err != nil {
 		return fmt.Errorf("failed to submit request: %w", err)
 	}
 
-	var raw json.RawMessage
-	if err := conn.ReadJSON(&raw); err != nil {
+	_, raw, err := conn.ReadMessage()
+	if err != nil {
 		return fmt.Errorf("failed to read json response: %w", err)
 	}
+	c.options.tap(FrameInbound, raw)
+	if !json.Valid(raw) {
+		return fmt.Errorf("failed to read json response: invalid json")
+	}
 
 	if bytes.Contains(raw, fault) {
 		var e Error
@@ -73,6 +141,9 @@ func (c *Client) query(ctx context.Context, payload interface{}, v interface{})
 		}
 		return e
 	}
+	if e, ok := decodeEraMismatch(raw); ok {
+		return e
+	}
 
 	if v != nil {
 		if err := json.Unmarshal(raw, v); err != nil {
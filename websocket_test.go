@@ -2,11 +2,13 @@ package ogmigo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -72,3 +74,297 @@ func TestClient_query(t *testing.T) {
 		t.Fatalf("expected context.Canceled; got %v", err)
 	}
 }
+
+func TestClient_queryCancel(t *testing.T) {
+	server := httptest.NewServer(timeout(time.Minute))
+	defer server.Close()
+
+	client := New(WithEndpoint("ws"+strings.TrimPrefix(server.URL, "http")), WithLogger(NopLogger))
+
+	tests := map[string]func(ctx context.Context) error{
+		"SubmitTx": func(ctx context.Context) error {
+			return client.SubmitTx(ctx, []byte(`{}`))
+		},
+		"SubmitTxBytes": func(ctx context.Context) error {
+			return client.SubmitTxBytes(ctx, []byte{0x84})
+		},
+		"EvaluateTx": func(ctx context.Context) error {
+			_, err := client.EvaluateTx(ctx, "84")
+			return err
+		},
+		"EvaluateTxBytes": func(ctx context.Context) error {
+			_, err := client.EvaluateTxBytes(ctx, []byte{0x84})
+			return err
+		},
+	}
+
+	for label, fn := range tests {
+		t.Run(label, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				cancel()
+			}()
+
+			begin := time.Now()
+			err := fn(ctx)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("got %v; want context.Canceled", err)
+			}
+			if elapsed := time.Since(begin); elapsed > 5*time.Second {
+				t.Fatalf("got %v; want prompt return", elapsed)
+			}
+		})
+	}
+}
+
+// queryServer replies to every request with the provided reply and forwards
+// each request received to the returned channel
+func queryServer(t *testing.T, reply string) (string, <-chan []byte) {
+	var (
+		upgrader = websocket.Upgrader{}
+		requests = make(chan []byte, 16)
+	)
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		c, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		for {
+			_, message, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			requests <- message
+
+			if err := c.WriteMessage(websocket.TextMessage, []byte(reply)); err != nil {
+				return
+			}
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http"), requests
+}
+
+func TestClient_queryErrorContext(t *testing.T) {
+	endpoint, requests := queryServer(t, `{"type":"jsonwsp/fault","version":"1.0","servicename":"ogmios","fault":{"code":"client","string":"invalid query"}}`)
+
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger))
+	_, err := client.ChainTip(context.Background())
+
+	var e Error
+	if ok := errors.As(err, &e); !ok {
+		t.Fatalf("got %v; want Error", err)
+	}
+	if got, want := err.Error(), "Query/ledgerTip (id="; !strings.HasPrefix(got, want) {
+		t.Fatalf("got %v; want prefix %v", got, want)
+	}
+	if got, want := string(<-requests), `"mirror":{"id":`; !strings.Contains(got, want) {
+		t.Fatalf("got %v; want contains %v", got, want)
+	}
+}
+
+func TestClient_queryIDGenerator(t *testing.T) {
+	endpoint, requests := queryServer(t, `{"type":"jsonwsp/fault","version":"1.0","servicename":"ogmios","fault":{"code":"client","string":"invalid query"}}`)
+
+	id := func() json.RawMessage { return json.RawMessage(`"abc-123"`) }
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithIDGenerator(id))
+	_, err := client.ChainTip(context.Background())
+	if got, want := err.Error(), `Query/ledgerTip (id="abc-123")`; !strings.HasPrefix(got, want) {
+		t.Fatalf("got %v; want prefix %v", got, want)
+	}
+	if got, want := string(<-requests), `"mirror":{"id":"abc-123"}`; !strings.Contains(got, want) {
+		t.Fatalf("got %v; want contains %v", got, want)
+	}
+}
+
+func TestClient_queryFrameTap(t *testing.T) {
+	reply := `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"Query","result":42}`
+	endpoint, requests := queryServer(t, reply)
+
+	var frames []string
+	tap := func(direction string, frame []byte) {
+		frames = append(frames, direction+" "+string(frame))
+	}
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithFrameTap(tap))
+	epoch, err := client.CurrentEpoch(context.Background())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := epoch, uint64(42); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+
+	if got, want := len(frames), 2; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := frames[0], FrameOutbound+" "+string(<-requests); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := frames[1], FrameInbound+" "+reply; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+}
+
+func Test_nextRequestID(t *testing.T) {
+	a, b := nextRequestID(), nextRequestID()
+
+	var x, y uint64
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if x >= y {
+		t.Fatalf("got %v, %v; want increasing ids", x, y)
+	}
+}
+
+func Test_methodName(t *testing.T) {
+	tests := map[string]struct {
+		Payload Map
+		Want    string
+	}{
+		"string query": {
+			Payload: makePayload("Query", Map{"query": "ledgerTip"}),
+			Want:    "Query/ledgerTip",
+		},
+		"map query": {
+			Payload: makePayload("Query", Map{"query": Map{"utxo": []string{"addr"}}}),
+			Want:    "Query/utxo",
+		},
+		"no query": {
+			Payload: makePayload("SubmitTx", Map{"submit": "84a4"}),
+			Want:    "SubmitTx",
+		},
+	}
+
+	for label, tc := range tests {
+		t.Run(label, func(t *testing.T) {
+			if got := methodName(tc.Payload); got != tc.Want {
+				t.Fatalf("got %v; want %v", got, tc.Want)
+			}
+		})
+	}
+}
+
+func TestClient_queryCompression(t *testing.T) {
+	extensions := make(chan string, 1)
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		extensions <- req.Header.Get("Sec-WebSocket-Extensions")
+		c, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		if _, _, err := c.ReadMessage(); err != nil {
+			return
+		}
+		_ = c.WriteMessage(websocket.TextMessage, []byte(`{"type":"jsonwsp/response","result":123}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	endpoint := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := New(WithEndpoint(endpoint), WithLogger(NopLogger), WithCompression(true))
+	epoch, err := client.CurrentEpoch(context.Background())
+	if err != nil {
+		t.Fatalf("got %v; want nil", err)
+	}
+	if got, want := epoch, uint64(123); got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	if got, want := <-extensions, "permessage-deflate"; !strings.Contains(got, want) {
+		t.Fatalf("got %v; want contains %v", got, want)
+	}
+	if websocket.DefaultDialer.EnableCompression {
+		t.Fatalf("got true; want DefaultDialer left unmodified")
+	}
+}
+
+// routedQueryServer replies to each Query with the result registered for its query; queries
+// taking arguments are registered by their json e.g. {"genesisConfig":"byron"}
+func routedQueryServer(t *testing.T, results map[string]string) string {
+	upgrader := websocket.Upgrader{}
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		c, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		for {
+			var request struct {
+				Args struct {
+					Query json.RawMessage
+				}
+			}
+			if err := c.ReadJSON(&request); err != nil {
+				return
+			}
+
+			query := strings.Trim(string(request.Args.Query), `"`)
+			result, ok := results[query]
+			if !ok {
+				t.Errorf("got unexpected query, %v", query)
+				return
+			}
+			reply := `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"Query","result":` + result + `}`
+			if err := c.WriteMessage(websocket.TextMessage, []byte(reply)); err != nil {
+				return
+			}
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// methodServer replies to each request with the result registered for its method and
+// forwards the method of each request received, on any connection, to the returned channel
+func methodServer(t *testing.T, results map[string]string) (string, <-chan string) {
+	var (
+		upgrader = websocket.Upgrader{}
+		methods  = make(chan string, 16)
+	)
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		c, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		for {
+			var request struct{ MethodName string }
+			if err := c.ReadJSON(&request); err != nil {
+				return
+			}
+			methods <- request.MethodName
+
+			result, ok := results[request.MethodName]
+			if !ok {
+				t.Errorf("got unexpected method, %v", request.MethodName)
+				return
+			}
+			reply := `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"` + request.MethodName + `","result":` + result + `}`
+			if err := c.WriteMessage(websocket.TextMessage, []byte(reply)); err != nil {
+				return
+			}
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http"), methods
+}